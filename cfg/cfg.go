@@ -157,8 +157,6 @@ func (s *Store) GetBool(section, key string) (output bool) {
 	default:
 		return false
 	}
-
-	return
 }
 
 // Get Int64 Value from config.
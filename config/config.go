@@ -0,0 +1,246 @@
+/*
+Package 'config' defines a schema of settings once, and uses it to load and save an INI file through cfg, pre-populate eflag defaults, and build an options menu.
+
+	conf := config.New()
+	server := conf.String("connection", "server", "localhost", "Server to connect to.")
+	port := conf.Int("connection", "port", 443, "Port to connect on.", 1, 65535)
+
+	flags := eflag.NewFlagSet(os.Args[0], eflag.ExitOnError)
+	conf.Load("app.ini")           // File values override defaults.
+	conf.Flags(flags)              // Flags default to file values, and override them when set.
+	flags.Parse(os.Args[1:])
+*/
+package config
+
+import (
+	"fmt"
+	"github.com/cmcoffee/snugforge/cfg"
+	"github.com/cmcoffee/snugforge/eflag"
+	"github.com/cmcoffee/snugforge/options"
+	"os"
+	"strconv"
+)
+
+const (
+	stringType = iota
+	secretType
+	boolType
+	intType
+)
+
+// Setting within the schema.
+type entry struct {
+	section string
+	key     string
+	usage   string
+	kind    int
+	str     *string
+	boolean *bool
+	num     *int
+	min     int
+	max     int
+}
+
+// Config is a schema of settings backed by a cfg.Store.
+type Config struct {
+	store   *cfg.Store
+	entries []*entry
+}
+
+// Creates a new Config.
+func New() *Config {
+	return &Config{
+		store:   new(cfg.Store),
+		entries: make([]*entry, 0),
+	}
+}
+
+// Returns the underlying cfg.Store.
+func (C *Config) Store() *cfg.Store {
+	return C.store
+}
+
+// Registers entry with Config.
+func (C *Config) register(e *entry) {
+	for _, v := range C.entries {
+		if v.section == e.section && v.key == e.key {
+			panic(fmt.Sprintf("config: [%s] %s registered twice", e.section, e.key))
+		}
+	}
+	C.entries = append(C.entries, e)
+}
+
+// Looks up entry by section and key.
+func (C *Config) lookup(section, key string) *entry {
+	for _, e := range C.entries {
+		if e.section == section && e.key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+// String defines a string setting under [section] key, with default value and usage string. The return value is the address of a string variable that stores the value of the setting.
+func (C *Config) String(section, key string, value string, usage string) *string {
+	p := new(string)
+	C.StringVar(p, section, key, value, usage)
+	return p
+}
+
+// StringVar defines a string setting under [section] key, with default value and usage string. The argument p points to a string variable in which to store the value of the setting.
+func (C *Config) StringVar(p *string, section, key string, value string, usage string) {
+	*p = value
+	C.register(&entry{section: section, key: key, usage: usage, kind: stringType, str: p})
+}
+
+// Secret defines a string setting which is masked in the options menu. The return value is the address of a string variable that stores the value of the setting.
+func (C *Config) Secret(section, key string, value string, usage string) *string {
+	p := new(string)
+	C.SecretVar(p, section, key, value, usage)
+	return p
+}
+
+// SecretVar defines a string setting which is masked in the options menu. The argument p points to a string variable in which to store the value of the setting.
+func (C *Config) SecretVar(p *string, section, key string, value string, usage string) {
+	*p = value
+	C.register(&entry{section: section, key: key, usage: usage, kind: secretType, str: p})
+}
+
+// Bool defines a bool setting under [section] key, with default value and usage string. The return value is the address of a bool variable that stores the value of the setting.
+func (C *Config) Bool(section, key string, value bool, usage string) *bool {
+	p := new(bool)
+	C.BoolVar(p, section, key, value, usage)
+	return p
+}
+
+// BoolVar defines a bool setting under [section] key, with default value and usage string. The argument p points to a bool variable in which to store the value of the setting.
+func (C *Config) BoolVar(p *bool, section, key string, value bool, usage string) {
+	*p = value
+	C.register(&entry{section: section, key: key, usage: usage, kind: boolType, boolean: p})
+}
+
+// Int defines an int setting under [section] key, with default value, usage string and range accepted by the options menu. The return value is the address of an int variable that stores the value of the setting.
+func (C *Config) Int(section, key string, value int, usage string, min, max int) *int {
+	p := new(int)
+	C.IntVar(p, section, key, value, usage, min, max)
+	return p
+}
+
+// IntVar defines an int setting under [section] key, with default value, usage string and range accepted by the options menu. The argument p points to an int variable in which to store the value of the setting.
+func (C *Config) IntVar(p *int, section, key string, value int, usage string, min, max int) {
+	*p = value
+	C.register(&entry{section: section, key: key, usage: usage, kind: intType, num: p, min: min, max: max})
+}
+
+// Reads the configuration file, values found in the file replace the defaults.
+// A missing file is not an error, it will be created on Save.
+func (C *Config) Load(file string) (err error) {
+	if err = C.store.File(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, e := range C.entries {
+		if !C.store.Exists(e.section, e.key) {
+			continue
+		}
+		value := C.store.SGet(e.section, e.key)
+		switch e.kind {
+		case stringType, secretType:
+			*e.str = value
+		case boolType:
+			*e.boolean = C.store.GetBool(e.section, e.key)
+		case intType:
+			num, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s: [%s] %s: %q is not an integer.", file, e.section, e.key, value)
+			}
+			*e.num = num
+		}
+	}
+	return nil
+}
+
+// Writes current values to the configuration file given to Load.
+func (C *Config) Save() error {
+	for _, e := range C.entries {
+		C.store.Set(e.section, e.key, C.value(e))
+	}
+	return C.store.Save()
+}
+
+// Returns the current value of entry as a string.
+func (C *Config) value(e *entry) string {
+	switch e.kind {
+	case boolType:
+		return strconv.FormatBool(*e.boolean)
+	case intType:
+		return strconv.Itoa(*e.num)
+	default:
+		return *e.str
+	}
+}
+
+// Returns current value of string setting.
+func (C *Config) GetString(section, key string) string {
+	if e := C.lookup(section, key); e != nil && e.str != nil {
+		return *e.str
+	}
+	return ""
+}
+
+// Returns current value of bool setting.
+func (C *Config) GetBool(section, key string) bool {
+	if e := C.lookup(section, key); e != nil && e.boolean != nil {
+		return *e.boolean
+	}
+	return false
+}
+
+// Returns current value of int setting.
+func (C *Config) GetInt(section, key string) int {
+	if e := C.lookup(section, key); e != nil && e.num != nil {
+		return *e.num
+	}
+	return 0
+}
+
+// Returns the flag name for entry, the key if unique, otherwise section-key.
+func (C *Config) flagName(e *entry) string {
+	for _, v := range C.entries {
+		if v != e && v.key == e.key && e.section != "" {
+			return fmt.Sprintf("%s-%s", e.section, e.key)
+		}
+	}
+	return e.key
+}
+
+// Registers each setting as a flag on flags, defaulting to the current (loaded) value, call after Load and before Parse.
+func (C *Config) Flags(flags *eflag.EFlagSet) {
+	for _, e := range C.entries {
+		name := C.flagName(e)
+		switch e.kind {
+		case stringType, secretType:
+			flags.StringVar(e.str, name, *e.str, e.usage)
+		case boolType:
+			flags.BoolVar(e.boolean, name, e.usage)
+		case intType:
+			flags.IntVar(e.num, name, *e.num, e.usage)
+		}
+	}
+}
+
+// Registers each setting with the options menu, the menu edits the same variables as Flags and Save.
+func (C *Config) Options(menu *options.Options) {
+	for _, e := range C.entries {
+		desc := e.key
+		switch e.kind {
+		case stringType:
+			menu.StringVar(e.str, desc, *e.str, e.usage)
+		case secretType:
+			menu.SecretVar(e.str, desc, *e.str, e.usage)
+		case boolType:
+			menu.BoolVar(e.boolean, desc, *e.boolean)
+		case intType:
+			menu.IntVar(e.num, desc, *e.num, e.usage, e.min, e.max)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Package 'creds' is a credential vault storing named secrets in a kvlite database, encrypted with CryptSet.
+The database padlock is derived from a master password with Argon2, the salt is kept beside the database in a ".salt" file.
+*/
+package creds
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/cmcoffee/snugforge/kvlite"
+	"github.com/cmcoffee/snugforge/nfo"
+	"golang.org/x/crypto/argon2"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("Secret not found.")
+	ErrExpired     = errors.New("Secret has expired.")
+	ErrBadPassword = kvlite.ErrBadPadlock
+	ErrNoSalt      = errors.New("Vault exists but salt file is missing, unable to derive master key.")
+	ErrMismatch    = errors.New("Passwords do not match.")
+)
+
+const (
+	secrets_table = "secrets"
+	salt_len      = 16
+)
+
+// Argon2id parameters used to derive the master key.
+var (
+	ArgonTime    uint32 = 1
+	ArgonMemory  uint32 = 64 * 1024
+	ArgonThreads uint8  = 4
+)
+
+// Metadata of a stored secret.
+type Info struct {
+	Name     string
+	Created  time.Time
+	LastUsed time.Time // Zero if never retrieved.
+	Expires  time.Time // Zero if the secret does not expire.
+}
+
+// Returns true if the secret has expired.
+func (I Info) Expired() bool {
+	return !I.Expires.IsZero() && time.Now().After(I.Expires)
+}
+
+type entry struct {
+	Value    string
+	Created  time.Time
+	LastUsed time.Time
+	Expires  time.Time
+}
+
+// Vault of named secrets.
+type Vault struct {
+	filename string
+	key      []byte
+	db       kvlite.Store
+	mutex    sync.Mutex
+}
+
+// Opens or creates the vault at filename, unlocked with password.
+func Open(filename string, password string) (*Vault, error) {
+	key, new_salt, err := deriveKey(filename, password)
+	if err != nil {
+		return nil, err
+	}
+	V, err := openKey(filename, key)
+	if err != nil || new_salt == nil {
+		return V, err
+	}
+	// Only keep the salt once the vault it belongs to exists.
+	if err := os.WriteFile(filename+".salt", new_salt, 0600); err != nil {
+		V.db.Close()
+		os.Remove(filename)
+		return nil, err
+	}
+	return V, nil
+}
+
+// Opens the vault with a derived master key.
+func openKey(filename string, key []byte) (*Vault, error) {
+	db, err := kvlite.Open(filename, key...)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{filename: filename, key: key, db: db}, nil
+}
+
+// Opens the vault at filename, using the master key from the OS keychain if remembered, otherwise prompting with nfo.GetSecret.
+// When creating a new vault the password is asked for twice.
+func Unlock(filename string, prompt string) (*Vault, error) {
+	if key, err := keychainGet(filename); err == nil && key != nil {
+		if V, err := openKey(filename, key); err == nil {
+			return V, nil
+		}
+	}
+
+	_, err := os.Stat(filename)
+	create := os.IsNotExist(err)
+
+	password := nfo.GetSecret(prompt)
+	if create {
+		if password != nfo.GetSecret("(confirm) "+prompt) {
+			return nil, ErrMismatch
+		}
+	}
+	return Open(filename, password)
+}
+
+// Derives the master key from password, new_salt is set for new vaults and must be saved once the vault is created.
+func deriveKey(filename, password string) (key []byte, new_salt []byte, err error) {
+	salt, err := os.ReadFile(filename + ".salt")
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, nil, err
+		}
+		if _, err := os.Stat(filename); err == nil {
+			return nil, nil, ErrNoSalt
+		}
+		salt = make([]byte, salt_len)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, err
+		}
+		new_salt = salt
+	}
+
+	return argon2.IDKey([]byte(password), salt, ArgonTime, ArgonMemory, ArgonThreads, 32), new_salt, nil
+}
+
+// Stores the secret under name, a ttl of 0 never expires. Replacing a secret keeps its created time.
+func (V *Vault) Set(name, value string, ttl time.Duration) error {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+
+	var e entry
+	found, err := V.db.Get(secrets_table, name, &e)
+	if err != nil {
+		return err
+	}
+	if !found {
+		e.Created = time.Now()
+	}
+	e.Value = value
+	e.Expires = time.Time{}
+	if ttl > 0 {
+		e.Expires = time.Now().Add(ttl)
+	}
+	return V.db.CryptSet(secrets_table, name, e)
+}
+
+// Retrieves the named secret, recording its last use. Returns ErrExpired for expired secrets.
+func (V *Vault) Get(name string) (string, error) {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+
+	var e entry
+	found, err := V.db.Get(secrets_table, name, &e)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", ErrNotFound
+	}
+	if !e.Expires.IsZero() && time.Now().After(e.Expires) {
+		return "", ErrExpired
+	}
+	e.LastUsed = time.Now()
+	if err := V.db.CryptSet(secrets_table, name, e); err != nil {
+		return "", err
+	}
+	return e.Value, nil
+}
+
+// Returns the metadata of the named secret, without recording a use.
+func (V *Vault) Info(name string) (info Info, err error) {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+
+	var e entry
+	found, err := V.db.Get(secrets_table, name, &e)
+	if err != nil {
+		return info, err
+	}
+	if !found {
+		return info, ErrNotFound
+	}
+	return Info{name, e.Created, e.LastUsed, e.Expires}, nil
+}
+
+// Returns the names of all stored secrets, sorted.
+func (V *Vault) List() ([]string, error) {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+
+	names, err := V.db.Keys(secrets_table)
+	sort.Strings(names)
+	return names, err
+}
+
+// Removes the named secret.
+func (V *Vault) Delete(name string) error {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+	return V.db.Unset(secrets_table, name)
+}
+
+// Removes all expired secrets, returns the names removed.
+func (V *Vault) Purge() (removed []string, err error) {
+	names, err := V.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		info, err := V.Info(name)
+		if err != nil {
+			return removed, err
+		}
+		if info.Expired() {
+			if err := V.Delete(name); err != nil {
+				return removed, err
+			}
+			removed = append(removed, name)
+		}
+	}
+	return removed, nil
+}
+
+// Saves the master key to the OS keychain, so Unlock opens the vault without prompting.
+// Returns ErrNoKeychain if no supported keychain is available.
+func (V *Vault) Remember() error {
+	return keychainSet(V.filename, V.key)
+}
+
+// Removes the master key from the OS keychain.
+func (V *Vault) Forget() error {
+	return keychainDelete(V.filename)
+}
+
+// Closes the vault.
+func (V *Vault) Close() error {
+	V.mutex.Lock()
+	defer V.mutex.Unlock()
+	for i := range V.key {
+		V.key[i] = 0
+	}
+	if err := V.db.Close(); err != nil {
+		return fmt.Errorf("creds: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package creds
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var ErrNoKeychain = errors.New("No supported OS keychain available.")
+
+const keychain_service = "snugforge-creds"
+
+// Keychain account for the vault, its absolute path.
+func keychainAccount(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		return abs
+	}
+	return filename
+}
+
+// Returns the command used to reach the OS keychain: macOS security, or secret-tool (libsecret) elsewhere.
+func keychainTool() (string, error) {
+	tool := "secret-tool"
+	if runtime.GOOS == "darwin" {
+		tool = "security"
+	} else if runtime.GOOS == "windows" {
+		return "", ErrNoKeychain
+	}
+	path, err := exec.LookPath(tool)
+	if err != nil {
+		return "", ErrNoKeychain
+	}
+	return path, nil
+}
+
+// Saves key to the keychain.
+func keychainSet(filename string, key []byte) error {
+	tool, err := keychainTool()
+	if err != nil {
+		return err
+	}
+	account := keychainAccount(filename)
+	secret := base64.StdEncoding.EncodeToString(key)
+
+	if runtime.GOOS == "darwin" {
+		// Run interactively, so the secret is passed on stdin rather than the command line where ps can see it.
+		cmd := exec.Command(tool, "-i")
+		cmd.Stdin = strings.NewReader(strings.Join([]string{"add-generic-password", "-U", "-s", quoteArg(keychain_service), "-a", quoteArg(account), "-w", quoteArg(secret)}, " ") + "\n")
+		return runInteractive(cmd)
+	}
+
+	cmd := exec.Command(tool, "store", "--label", keychain_service+": "+account, "service", keychain_service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return runTool(cmd)
+}
+
+// Retrieves key from the keychain, nil if not found.
+func keychainGet(filename string) ([]byte, error) {
+	tool, err := keychainTool()
+	if err != nil {
+		return nil, err
+	}
+	account := keychainAccount(filename)
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(tool, "find-generic-password", "-s", keychain_service, "-a", account, "-w")
+	} else {
+		cmd = exec.Command(tool, "lookup", "service", keychain_service, "account", account)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil
+	}
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(string(out))
+}
+
+// Removes key from the keychain.
+func keychainDelete(filename string) error {
+	tool, err := keychainTool()
+	if err != nil {
+		return err
+	}
+	account := keychainAccount(filename)
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command(tool, "delete-generic-password", "-s", keychain_service, "-a", account)
+	} else {
+		cmd = exec.Command(tool, "clear", "service", keychain_service, "account", account)
+	}
+	return runTool(cmd)
+}
+
+// Runs security -i, which exits cleanly even when a command fails, so anything written to stderr is the error.
+func runInteractive(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// Quotes arg for a command read by security -i.
+func quoteArg(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// Runs a keychain command, including its output in any error.
+func runTool(cmd *exec.Cmd) error {
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -2,75 +2,428 @@ package csvp
 
 import (
 	"bufio"
+	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"github.com/cmcoffee/snugforge/swapreader"
+	"github.com/cmcoffee/snugforge/xsync"
 	"io"
 	"strings"
+	"unicode/utf8"
 )
 
-type rowReadError error
-type rowProcessError error
+var ErrStopped = errors.New("CSV read was stopped before completion.")
+
+const (
+	stopped = 1 << iota
+)
+
+// Error from reading or decoding the CSV, see IsReadError.
+type ReadError struct {
+	err error
+}
+
+func (R *ReadError) Error() string {
+	return R.err.Error()
+}
+
+func (R *ReadError) Unwrap() error {
+	return R.err
+}
+
+// Error returned by the Processor for a row, see IsRowError.
+type ProcessError struct {
+	err error
+}
+
+func (P *ProcessError) Error() string {
+	return P.err.Error()
+}
+
+func (P *ProcessError) Unwrap() error {
+	return P.err
+}
+
+// Error from a row or header with missing or unexpected columns, see IsColumnError.
+type ColumnError struct {
+	err error
+}
+
+func (C *ColumnError) Error() string {
+	return C.err.Error()
+}
+
+func (C *ColumnError) Unwrap() error {
+	return C.err
+}
+
+// ProgressBar receives bytes consumed during a Read, satisfied by nfo.ProgressBar.
+type ProgressBar interface {
+	Set(num int)
+	Done()
+}
+
+// Maps header column names to the cells of a row.
+type FieldsByName map[string]string
 
 type CSVReader struct {
-	Processor    func(row []string) (err error)                     // Callback funcction for each row read.
-	ErrorHandler func(line int, row string, err error) (abort bool) // ErrorHandler when problem reading CSV or processing CSV.
+	Processor      func(row []string) (err error)                     // Callback funcction for each row read.
+	NamedProcessor func(row FieldsByName) (err error)                 // Callback function for each row read, cells keyed by header column, requires SkipHeader.
+	RowProcessor   func(row *Row) (err error)                         // Callback function for each row read, with line number, raw text, and typed accessors by header column.
+	ErrorHandler   func(line int, row string, err error) (abort bool) // ErrorHandler when problem reading CSV or processing CSV.
+	SkipHeader     bool                                               // First row is a header, it is not passed to the Processor and is available from Header().
+	SkipBlank      bool                                               // Skip empty or whitespace only lines, rather than reporting them as read errors.
+	CollectErrors  bool                                               // Collect all row errors into a Report, available after Read from Report().
+	Checkpoint     func(line int)                                     // Callback after each row is handled, including rows with errors which did not abort the Read, resume after it with StartAt(line + 1).
+	Progress       func(rows int, bytes int64)                        // Callback after each line read, with rows processed and bytes consumed so far, bytes are of the input as given, ie.. compressed bytes of gzip input.
+	header         []string
+	columns        int
+	required       []string
+	validators     []*Column
+	comment        *string
+	skip_rows      int
+	start_at       int
+	bar            ProgressBar
+	report         *Report
+	flags          xsync.BitFlag
 }
 
 // Allocates a New CSVReader.
 func NewReader() *CSVReader {
 	return &CSVReader{
-		func(row []string) (err error) {
+		Processor: func(row []string) (err error) {
 			return nil
 		},
-		func(line int, input string, err error) (abort bool) {
+		ErrorHandler: func(line int, input string, err error) (abort bool) {
 			return false
 		},
 	}
 }
 
+// Returns the header row of the last Read, when SkipHeader is set.
+func (T *CSVReader) Header() []string {
+	return append([]string(nil), T.header...)
+}
+
+// Maps the cells of row to the header column names.
+func (T *CSVReader) fieldsByName(row []string) FieldsByName {
+	fields := make(FieldsByName, len(T.header))
+	for i, name := range T.header {
+		if i < len(row) {
+			fields[name] = row[i]
+		} else {
+			fields[name] = ""
+		}
+	}
+	return fields
+}
+
+// Error encountered on a row of the CSV.
+type RowError struct {
+	Line int    // Line number of the row.
+	Raw  string // Raw text of the line.
+	Err  error  // Error encountered.
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Report of a Read, when CollectErrors is set.
+type Report struct {
+	Rows   int        // Rows handed to the Processor.
+	Errors []RowError // Errors encountered, in order of line.
+}
+
+// Returns true if any errors were collected.
+func (R *Report) Failed() bool {
+	return len(R.Errors) > 0
+}
+
+// Returns the Report of the last Read, nil if CollectErrors was not set.
+func (T *CSVReader) Report() *Report {
+	return T.report
+}
+
+// Records err in the report if collecting errors, then passes it to the ErrorHandler.
+func (T *CSVReader) handleError(line int, raw string, err error) (abort bool) {
+	if T.report != nil {
+		T.report.Errors = append(T.report.Errors, RowError{line, raw, err})
+	}
+	if T.ErrorHandler != nil {
+		return T.ErrorHandler(line, raw, err)
+	}
+	return false
+}
+
 // Returns true if error is generatored from reading the CSV.
 func IsReadError(err error) bool {
-	if _, ok := err.(rowReadError); ok {
-		return true
+	var r_err *ReadError
+	return errors.As(err, &r_err)
+}
+
+// Returns true if error is generated from a row with missing or unexpected columns.
+func IsColumnError(err error) bool {
+	var c_err *ColumnError
+	return errors.As(err, &c_err)
+}
+
+// Lines starting with prefix are skipped as comments, defaults to "#", an empty prefix disables comments.
+func (T *CSVReader) CommentPrefix(prefix string) {
+	T.comment = &prefix
+}
+
+// Skips the first n lines of input, before any header, for exports with a preamble.
+func (T *CSVReader) SkipRows(n int) {
+	T.skip_rows = n
+}
+
+// Resumes processing at line, lines before it are skipped other than the header.
+func (T *CSVReader) StartAt(line int) {
+	T.start_at = line
+}
+
+// Rows without exactly n columns are sent to the ErrorHandler instead of the Processor, 0 disables the check.
+func (T *CSVReader) ExpectColumns(n int) {
+	T.columns = n
+}
+
+// Header must contain the named columns, and rows must provide a cell for each, implies SkipHeader.
+// A header missing required columns is sent to the ErrorHandler and the Read is aborted.
+func (T *CSVReader) RequireColumns(name ...string) {
+	T.required = name
+}
+
+// Checks the header for required columns.
+func (T *CSVReader) checkHeader() error {
+	required := T.required[0:len(T.required):len(T.required)]
+	for _, c := range T.validators {
+		required = append(required, c.name)
 	}
-	return false
+
+	var missing []string
+	for _, name := range required {
+		found := false
+		for _, col := range T.header {
+			if col == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &ColumnError{fmt.Errorf("header is missing required column(s): %s", strings.Join(missing, ", "))}
+	}
+	return nil
+}
+
+// Checks that row has the expected columns.
+func (T *CSVReader) checkRow(row []string) error {
+	if T.columns > 0 && len(row) != T.columns {
+		return &ColumnError{fmt.Errorf("expected %d columns, found %d", T.columns, len(row))}
+	}
+	for _, name := range T.required {
+		for i, col := range T.header {
+			if col == name && i >= len(row) {
+				return &ColumnError{fmt.Errorf("missing required column '%s'", name)}
+			}
+		}
+	}
+	return nil
 }
 
 // Returns true if error is generated from processing the row of the CSV.
 func IsRowError(err error) bool {
-	if _, ok := err.(rowProcessError); ok {
-		return true
-	}
-	return false
+	var p_err *ProcessError
+	return errors.As(err, &p_err)
+}
+
+// Displays bytes consumed on bar for the next Read, ie.. nfo.NewProgressBar(name, file_size), bar is marked done when the Read completes.
+func (T *CSVReader) ShowProgress(bar ProgressBar) {
+	T.bar = bar
 }
 
-// Reads incoming CSV data.
+// Stops a Read in progress, the Read returns after the current row.
+func (T *CSVReader) Stop() {
+	T.flags.Set(stopped)
+}
+
+// Reads incoming CSV data, gzip compressed input and UTF-16 input with a byte order mark are decoded automatically.
+// Lines which are not valid UTF-8 are treated as Windows-1252.
 func (T *CSVReader) Read(reader io.Reader) {
+	T.ReadContext(context.Background(), reader)
+}
+
+// Reads incoming CSV data until complete or ctx is done, returns the number of rows handed to the Processor.
+// err is ctx.Err() if cancelled, ErrStopped if Stop was called, or the error which caused the ErrorHandler to abort.
+func (T *CSVReader) ReadContext(ctx context.Context, reader io.Reader) (processed int, err error) {
+	return T.read(ctx, reader, func(line int, raw string, row []string) (err error) {
+		if T.Processor != nil {
+			if err = T.Processor(row); err != nil {
+				return err
+			}
+		}
+		if T.NamedProcessor != nil && T.header != nil {
+			if err = T.NamedProcessor(T.fieldsByName(row)); err != nil {
+				return err
+			}
+		}
+		if T.RowProcessor != nil {
+			return T.RowProcessor(&Row{Cells: row, Line: line, Raw: raw, header: T.header})
+		}
+		return nil
+	}, T.SkipHeader)
+}
+
+// Reads incoming CSV data, passing each row to processor, if header is set, the first row is stored as the header.
+func (T *CSVReader) read(ctx context.Context, reader io.Reader, processor func(line int, raw string, row []string) error, header bool) (processed int, err error) {
+	T.header = nil
+	T.flags.Unset(stopped)
+
+	T.report = nil
+	if T.CollectErrors {
+		T.report = new(Report)
+		defer func() { T.report.Rows = processed }()
+	}
 	line := 0
+
+	var consumed, position int64
+
+	// Decoded input is measured by bytes read from the source, as its lines differ in length from the input.
+	source := &countingReader{src: reader}
+	reader, decoded, err := decodeInput(source)
+	if err != nil {
+		T.handleError(0, "", &ReadError{err})
+		return 0, err
+	}
+
 	scanner := bufio.NewScanner(reader)
+	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		advance, token, err = bufio.ScanLines(data, atEOF)
+		consumed += int64(advance)
+		return
+	})
+
+	bar := T.bar
+	T.bar = nil
+	if bar != nil {
+		defer bar.Done()
+	}
+
+	progress := func() {
+		if T.Progress != nil {
+			T.Progress(processed, position)
+		}
+		if bar != nil {
+			bar.Set(int(position))
+		}
+	}
+
+	checkpoint := func(line int) {
+		if T.Checkpoint != nil {
+			T.Checkpoint(line)
+		}
+	}
+
 	swap := new(swapreader.Reader)
 	csv_reader := csv.NewReader(swap)
+	if T.columns > 0 || len(T.required) > 0 {
+		csv_reader.FieldsPerRecord = -1
+	}
+	if len(T.required) > 0 || len(T.validators) > 0 {
+		header = true
+	}
+
+	comment := "#"
+	if T.comment != nil {
+		comment = *T.comment
+	}
+
 	for scanner.Scan() {
+		// Report on the previous line.
+		if line > 0 {
+			progress()
+		}
+		position = consumed
+		if decoded {
+			position = source.n
+		}
+		if T.flags.Has(stopped) {
+			return processed, ErrStopped
+		}
+		if err = ctx.Err(); err != nil {
+			return processed, err
+		}
 		line++
 		data := scanner.Bytes()
-		if strings.HasPrefix(string(data), "#") {
+		if !utf8.Valid(data) {
+			data = fromCP1252(data)
+		}
+		if line <= T.skip_rows {
+			continue
+		}
+		if comment != "" && strings.HasPrefix(string(data), comment) {
+			continue
+		}
+		if T.SkipBlank && len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		if line < T.start_at && (!header || T.header != nil) {
 			continue
 		}
 		swap.SetBytes(data)
-		row, err := csv_reader.Read()
-		if err != nil {
-			if T.ErrorHandler != nil {
-				if T.ErrorHandler(line, string(data), rowReadError(err)) {
-					return
+		row, r_err := csv_reader.Read()
+		if r_err != nil {
+			if T.handleError(line, string(data), &ReadError{r_err}) {
+				return processed, r_err
+			}
+			checkpoint(line)
+			continue
+		}
+		if header && T.header == nil {
+			T.header = make([]string, len(row))
+			for i, name := range row {
+				T.header[i] = strings.TrimSpace(name)
+			}
+			if c_err := T.checkHeader(); c_err != nil {
+				T.handleError(line, string(data), c_err)
+				return processed, c_err
+			}
+			continue
+		}
+		if c_err := T.checkRow(row); c_err != nil {
+			if T.handleError(line, string(data), c_err) {
+				return processed, c_err
+			}
+			checkpoint(line)
+			continue
+		}
+		if v_errs := T.validate(line, row); len(v_errs) > 0 {
+			for _, v_err := range v_errs {
+				if T.handleError(line, string(data), v_err) {
+					return processed, v_err
 				}
 			}
+			checkpoint(line)
+			continue
 		}
-		if T.Processor != nil {
-			if err = T.Processor(row); err != nil {
-				if T.ErrorHandler(line, string(data), rowProcessError(err)) {
-					return
+		if processor != nil {
+			processed++
+			if p_err := processor(line, string(data), row); p_err != nil {
+				if T.handleError(line, string(data), &ProcessError{p_err}) {
+					return processed, p_err
 				}
 			}
 		}
+		checkpoint(line)
+	}
+	if decoded {
+		position = source.n
 	}
+	progress()
+	return processed, scanner.Err()
 }
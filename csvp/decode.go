@@ -0,0 +1,198 @@
+package csvp
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	durationType    = reflect.TypeOf(time.Duration(0))
+	timeType        = reflect.TypeOf(time.Time{})
+)
+
+// Maps a header column to a struct field.
+type fieldMap struct {
+	column int
+	name   string
+	index  []int
+}
+
+// Reads incoming CSV data, the first row is the header which is mapped to the struct fields of output.
+// Columns are matched exactly, as with Row.Get, to fields by the `csv:"column"` tag, or by field name when no tag is present, `csv:"-"` skips a field.
+// Output must be a pointer to a slice of structs (or struct pointers), or a func taking a struct (or struct pointer) and optionally returning an error.
+func (T *CSVReader) ReadStruct(reader io.Reader, output interface{}) (err error) {
+	out := reflect.ValueOf(output)
+
+	var (
+		elem   reflect.Type
+		emit   func(v reflect.Value) error
+		is_ptr bool
+	)
+
+	switch {
+	case out.Kind() == reflect.Ptr && out.Elem().Kind() == reflect.Slice:
+		slice := out.Elem()
+		elem = slice.Type().Elem()
+		emit = func(v reflect.Value) error {
+			slice.Set(reflect.Append(slice, v))
+			return nil
+		}
+	case out.Kind() == reflect.Func && out.Type().NumIn() == 1 && out.Type().NumOut() <= 1:
+		if out.Type().NumOut() == 1 && out.Type().Out(0) != errorType {
+			return fmt.Errorf("csvp: callback must return nothing or an error, not %s", out.Type().Out(0))
+		}
+		elem = out.Type().In(0)
+		emit = func(v reflect.Value) error {
+			res := out.Call([]reflect.Value{v})
+			if len(res) > 0 && !res[0].IsNil() {
+				return res[0].Interface().(error)
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("csvp: ReadStruct requires a pointer to a slice or a callback function, not %T", output)
+	}
+
+	if elem.Kind() == reflect.Ptr {
+		is_ptr = true
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("csvp: ReadStruct requires struct elements, not %s", elem)
+	}
+
+	var fields []fieldMap
+
+	_, err = T.read(context.Background(), reader, func(line int, raw string, row []string) error {
+		if fields == nil {
+			fields = mapFields(elem, T.header)
+		}
+		v := reflect.New(elem)
+		for _, f := range fields {
+			if f.column >= len(row) {
+				continue
+			}
+			if err := setValue(v.Elem().FieldByIndex(f.index), row[f.column]); err != nil {
+				return fmt.Errorf("column '%s': %s", f.name, err)
+			}
+		}
+		if !is_ptr {
+			v = v.Elem()
+		}
+		return emit(v)
+	}, true)
+
+	return err
+}
+
+// Maps header columns to fields of struct type t.
+func mapFields(t reflect.Type, header []string) (fields []fieldMap) {
+	fields = make([]fieldMap, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag = strings.Split(tag, ",")[0]; tag != "" {
+				name = tag
+			}
+		}
+		for n, col := range header {
+			if col == name {
+				fields = append(fields, fieldMap{n, name, field.Index})
+				break
+			}
+		}
+	}
+	return
+}
+
+// Converts input to the type of v and stores it.
+func setValue(v reflect.Value, input string) (err error) {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshaler) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(input))
+	}
+
+	if v.Kind() == reflect.String {
+		v.SetString(input)
+		return nil
+	}
+
+	input = strings.TrimSpace(input)
+
+	switch v.Type() {
+	case durationType:
+		if input == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(input)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		if input == "" {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, input)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if input == "" {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(input)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(input, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(input, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(input, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err = setValue(p.Elem(), input); err != nil {
+			return err
+		}
+		v.Set(p)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
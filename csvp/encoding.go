@@ -0,0 +1,126 @@
+package csvp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Counts bytes read from the source, ie.. compressed bytes of gzip input.
+type countingReader struct {
+	src io.Reader
+	n   int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.src.Read(p)
+	c.n += int64(n)
+	return
+}
+
+// Detects gzip compression and byte order marks, returning a reader of UTF-8 text.
+// decoded is true if the text is not the input as given, so its length does not match the bytes of input.
+func decodeInput(reader io.Reader) (output io.Reader, decoded bool, err error) {
+	input := bufio.NewReader(reader)
+
+	if magic, _ := input.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(input)
+		if err != nil {
+			return nil, false, err
+		}
+		input = bufio.NewReader(gz)
+		decoded = true
+	}
+
+	bom, _ := input.Peek(3)
+	switch {
+	case bytes.HasPrefix(bom, []byte{0xef, 0xbb, 0xbf}):
+		input.Discard(3)
+	case bytes.HasPrefix(bom, []byte{0xff, 0xfe}):
+		input.Discard(2)
+		return &utf16Reader{src: input}, true, nil
+	case bytes.HasPrefix(bom, []byte{0xfe, 0xff}):
+		input.Discard(2)
+		return &utf16Reader{src: input, big_endian: true}, true, nil
+	}
+	return input, decoded, nil
+}
+
+// Transcodes UTF-16 input to UTF-8.
+type utf16Reader struct {
+	src        *bufio.Reader
+	big_endian bool
+	pending    []byte
+	err        error
+}
+
+// Reads a single UTF-16 code unit.
+func (u *utf16Reader) unit() (uint16, error) {
+	var b [2]byte
+	if _, err := io.ReadFull(u.src, b[0:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		return 0, err
+	}
+	if u.big_endian {
+		return uint16(b[0])<<8 | uint16(b[1]), nil
+	}
+	return uint16(b[1])<<8 | uint16(b[0]), nil
+}
+
+func (u *utf16Reader) Read(p []byte) (n int, err error) {
+	for len(u.pending) < len(p) && u.err == nil {
+		var r rune
+		c, err := u.unit()
+		if err != nil {
+			u.err = err
+			break
+		}
+		r = rune(c)
+		if utf16.IsSurrogate(r) {
+			c2, err := u.unit()
+			if err != nil {
+				u.err = err
+				r = utf8.RuneError
+			} else {
+				r = utf16.DecodeRune(r, rune(c2))
+			}
+		}
+		u.pending = utf8.AppendRune(u.pending, r)
+		if u.src.Buffered() == 0 {
+			break
+		}
+	}
+	n = copy(p, u.pending)
+	u.pending = u.pending[n:]
+	if n == 0 && u.err != nil {
+		return 0, u.err
+	}
+	return n, nil
+}
+
+// Windows-1252 characters in the 0x80 - 0x9f range, the rest of the range maps directly to Latin-1.
+var cp1252 = [32]rune{
+	'€', 0xfffd, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0xfffd, 'Ž', 0xfffd,
+	0xfffd, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0xfffd, 'ž', 'Ÿ',
+}
+
+// Converts a line of non UTF-8 text, assumed to be Windows-1252 (or Latin-1), to UTF-8.
+func fromCP1252(input []byte) []byte {
+	output := make([]byte, 0, len(input)*2)
+	for _, b := range input {
+		switch {
+		case b < 0x80:
+			output = append(output, b)
+		case b < 0xa0:
+			output = utf8.AppendRune(output, cp1252[b-0x80])
+		default:
+			output = utf8.AppendRune(output, rune(b))
+		}
+	}
+	return output
+}
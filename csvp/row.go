@@ -0,0 +1,92 @@
+package csvp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Row of CSV data, cells are accessed by header column name.
+type Row struct {
+	Cells  []string // Cells of the row.
+	Line   int      // Line number of the row in the source.
+	Raw    string   // Raw text of the line.
+	header []string
+}
+
+// Returns the cell for column, errors if the column is not in the header or the row.
+func (R *Row) Get(column string) (string, error) {
+	for i, name := range R.header {
+		if name == column {
+			if i < len(R.Cells) {
+				return R.Cells[i], nil
+			}
+			return "", R.error(column, "no value in row")
+		}
+	}
+	return "", R.error(column, "column not found in header")
+}
+
+// Returns the cell for column as an int.
+func (R *Row) GetInt(column string) (int, error) {
+	cell, err := R.Get(column)
+	if err != nil {
+		return 0, err
+	}
+	val, err := strconv.Atoi(strings.TrimSpace(cell))
+	if err != nil {
+		return 0, R.error(column, fmt.Sprintf("%q is not an integer", cell))
+	}
+	return val, nil
+}
+
+// Returns the cell for column as a float64.
+func (R *Row) GetFloat(column string) (float64, error) {
+	cell, err := R.Get(column)
+	if err != nil {
+		return 0, err
+	}
+	val, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	if err != nil {
+		return 0, R.error(column, fmt.Sprintf("%q is not a number", cell))
+	}
+	return val, nil
+}
+
+// Returns the cell for column as a bool, accepts yes/no in addition to strconv.ParseBool values.
+func (R *Row) GetBool(column string) (bool, error) {
+	cell, err := R.Get(column)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(cell)) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	val, err := strconv.ParseBool(strings.TrimSpace(cell))
+	if err != nil {
+		return false, R.error(column, fmt.Sprintf("%q is not a boolean", cell))
+	}
+	return val, nil
+}
+
+// Returns the cell for column as a time.Time parsed with layout.
+func (R *Row) GetTime(column string, layout string) (time.Time, error) {
+	cell, err := R.Get(column)
+	if err != nil {
+		return time.Time{}, err
+	}
+	val, err := time.Parse(layout, strings.TrimSpace(cell))
+	if err != nil {
+		return time.Time{}, R.error(column, fmt.Sprintf("%q does not match time layout %q", cell, layout))
+	}
+	return val, nil
+}
+
+// Generates an error referencing line and column.
+func (R *Row) error(column string, msg string) error {
+	return fmt.Errorf("line %d, column '%s': %s", R.Line, column, msg)
+}
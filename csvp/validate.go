@@ -0,0 +1,127 @@
+package csvp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validation rules for a column, evaluated before the row is handed to the Processor.
+type Column struct {
+	name  string
+	rules []func(value string) (reason string)
+}
+
+// Validation error for a cell of the CSV.
+type ValidationError struct {
+	Line   int    // Line number of the row.
+	Column string // Column name.
+	Value  string // Value of the cell.
+	Reason string // Reason the value was rejected.
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("column '%s': %q %s", e.Column, e.Value, e.Reason)
+}
+
+// Returns true if error is generated from a cell failing column validation.
+func IsValidationError(err error) bool {
+	_, ok := err.(*ValidationError)
+	return ok
+}
+
+// Returns validation rules for the named column, creating them if needed, implies SkipHeader.
+// A header without the column is sent to the ErrorHandler and the Read is aborted.
+func (T *CSVReader) Column(name string) *Column {
+	for _, c := range T.validators {
+		if c.name == name {
+			return c
+		}
+	}
+	c := &Column{name: name}
+	T.validators = append(T.validators, c)
+	return c
+}
+
+// Adds a rule, fn returns a reason when value is rejected.
+func (C *Column) rule(fn func(value string) (reason string)) *Column {
+	C.rules = append(C.rules, fn)
+	return C
+}
+
+// Value must match re.
+func (C *Column) Match(re *regexp.Regexp) *Column {
+	return C.rule(func(value string) string {
+		if !re.MatchString(value) {
+			return fmt.Sprintf("does not match pattern %s", re)
+		}
+		return ""
+	})
+}
+
+// Value must be a number between min and max, inclusive.
+func (C *Column) Range(min, max float64) *Column {
+	return C.rule(func(value string) string {
+		num, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "is not a number"
+		}
+		if num < min || num > max {
+			return fmt.Sprintf("is outside of range %v to %v", min, max)
+		}
+		return ""
+	})
+}
+
+// Value must not be empty.
+func (C *Column) NotEmpty() *Column {
+	return C.rule(func(value string) string {
+		if len(strings.TrimSpace(value)) == 0 {
+			return "is empty"
+		}
+		return ""
+	})
+}
+
+// Value must be one of values.
+func (C *Column) OneOf(values ...string) *Column {
+	return C.rule(func(value string) string {
+		for _, v := range values {
+			if v == value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("is not one of: %s", strings.Join(values, ", "))
+	})
+}
+
+// Value must pass fn, the error returned by fn is used as the reason.
+func (C *Column) Check(fn func(value string) error) *Column {
+	return C.rule(func(value string) string {
+		if err := fn(value); err != nil {
+			return err.Error()
+		}
+		return ""
+	})
+}
+
+// Validates the cells of row, returning an error for each rejected cell.
+func (T *CSVReader) validate(line int, row []string) (errs []error) {
+	for _, c := range T.validators {
+		var value string
+		for i, name := range T.header {
+			if name == c.name && i < len(row) {
+				value = row[i]
+				break
+			}
+		}
+		for _, rule := range c.rules {
+			if reason := rule(value); reason != "" {
+				errs = append(errs, &ValidationError{line, c.name, value, reason})
+				break
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package eflag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Replaces flag values starting with '@' with the contents of the file named.
+func (s *EFlagSet) expandFiles(args []string) (output []string, err error) {
+	output = append([]string(nil), args...)
+	for i := 0; i < len(output); i++ {
+		a := output[i]
+		if a == "--" {
+			break
+		}
+		if len(a) < 2 || a[0] != '-' {
+			continue
+		}
+		name := strings.TrimLeft(a, "-")
+		if n := strings.Index(name, "="); n > -1 {
+			f := s.Lookup(name[:n])
+			if f == nil || !strings.HasPrefix(name[n+1:], "@") {
+				continue
+			}
+			value, err := readValue(f, name[n+1:])
+			if err != nil {
+				return args, err
+			}
+			output[i] = a[:len(a)-len(name)+n+1] + value
+			continue
+		}
+		f := s.Lookup(name)
+		if f == nil || isBoolFlag(f) || i+1 >= len(output) {
+			continue
+		}
+		i++
+		if strings.HasPrefix(output[i], "@") {
+			if output[i], err = readValue(f, output[i]); err != nil {
+				return args, err
+			}
+		}
+	}
+	return output, nil
+}
+
+// Reads the value of '@file' for flag f, lines of the file are joined as items for Multi and slice flags.
+func readValue(f *Flag, value string) (string, error) {
+	if strings.HasPrefix(value, "@@") {
+		return value[1:], nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if value == "@-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(value[1:])
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read value for %s: %s", flagName(f.Name), err)
+	}
+
+	switch f.Value.(type) {
+	case *multiValue, *sliceValue:
+		var items []string
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				items = append(items, line)
+			}
+		}
+		return escape_array(items), nil
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,130 @@
+package eflag
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Subcommand registered with Command.
+type command struct {
+	name  string
+	usage string
+	run   func(flags *EFlagSet) error
+	flags *EFlagSet
+}
+
+// Registers a named subcommand, ie.. 'tool sync', and returns its flag set for defining the subcommand's flags, header and footer.
+// Parse dispatches to the subcommand named by the first non-flag argument, then calls run if it is not nil.
+func (s *EFlagSet) Command(name string, usage string, run func(flags *EFlagSet) error) *EFlagSet {
+	flags := NewFlagSet(name, s.errorHandling)
+	flags.syntaxName = fmt.Sprintf("%s %s", s.syntaxName, name)
+	flags.ShowSyntax = true
+	flags.out = s.out
+	s.commands = append(s.commands, &command{name, usage, run, flags})
+	return flags
+}
+
+// Returns the name and flag set of the subcommand selected by Parse, flags is nil if no subcommand was given.
+func (s *EFlagSet) Subcommand() (name string, flags *EFlagSet) {
+	if s.selected == nil {
+		return "", nil
+	}
+	return s.selected.name, s.selected.flags
+}
+
+// Parses flags before the subcommand, then hands the remaining arguments to the subcommand.
+func (s *EFlagSet) parseCommand(args []string) (err error) {
+	s.selected = nil
+
+	n := s.findCommand(args)
+	if err = s.parse(args[:n]); err != nil || n == len(args) {
+		return err
+	}
+
+	name := args[n]
+	for _, c := range s.commands {
+		if c.name == name {
+			s.selected = c
+			break
+		}
+	}
+	if s.selected == nil {
+		err = fmt.Errorf("unknown command: %s", name)
+		if s.errorHandling != ReturnErrorOnly {
+			fmt.Fprintf(s.out, "%s\n\n", err.Error())
+		}
+		s.handleError(err)
+		return err
+	}
+
+	if err = s.selected.flags.Parse(args[n+1:]); err != nil {
+		return err
+	}
+	if s.selected.run != nil {
+		return s.selected.run(s.selected.flags)
+	}
+	return nil
+}
+
+// Returns the index of the subcommand name in args, or len(args) if none is given.
+// Arguments after "--" are never a subcommand, and a lone "-" is skipped as the usual name for stdin.
+func (s *EFlagSet) findCommand(args []string) int {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			return len(args)
+		}
+		if a == "-" {
+			continue
+		}
+		if a == "" || a[0] != '-' {
+			return i
+		}
+		if strings.Contains(a, "=") {
+			continue
+		}
+		// Non-bool flags take the next argument as their value.
+		f := s.Lookup(strings.TrimLeft(a, "-"))
+		if f == nil {
+			if s.PassUnknown && s.isUnknown(a) && s.unknownValue(a, args[i+1:]) {
+				i++
+			}
+			continue
+		}
+		if isBoolFlag(f) {
+			continue
+		}
+		i++
+	}
+	return len(args)
+}
+
+// Applies errorHandling after a parse error.
+func (s *EFlagSet) handleError(err error) {
+	switch s.errorHandling {
+	case ReturnErrorOnly:
+	case ContinueOnError:
+		s.Usage()
+	case ExitOnError:
+		s.Usage()
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+}
+
+// Lists the registered subcommands for usage.
+func (s *EFlagSet) printCommands() {
+	if len(s.commands) == 0 {
+		return
+	}
+	fmt.Fprintf(s.out, "\nAvailable commands:\n")
+	output := tabwriter.NewWriter(s.out, 1, 1, 3, ' ', 0)
+	for _, c := range s.commands {
+		fmt.Fprintf(output, "  %s\t%s\n", c.name, c.usage)
+	}
+	output.Flush()
+	fmt.Fprintf(s.out, "\nUse '%s <command> --help' for command options.\n", s.syntaxName)
+}
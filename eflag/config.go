@@ -0,0 +1,162 @@
+package eflag
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Registers a config file to be loaded by Parse, a missing file is ignored.
+func (s *EFlagSet) ConfigFile(path string) {
+	s.config_file = path
+}
+
+// Returns where the value of flag name came from: "flag", "env", "file", "prompt", or "default".
+func (s *EFlagSet) Source(name string) string {
+	if src, ok := s.sources[name]; ok {
+		return src
+	}
+	return "default"
+}
+
+// Loads flag values from an INI or TOML style file of 'name = value' lines, values already set on the command line or environment are kept.
+// Strings may be quoted, arrays ie.. ["a", "b"] are loaded into Multi flags, and a [section] holds values for the subcommand of that name.
+func (s *EFlagSet) ParseFile(path string) (err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	target := s
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		txt := strings.TrimSpace(scanner.Text())
+		if len(txt) == 0 || txt[0] == '#' || txt[0] == ';' {
+			continue
+		}
+
+		if txt[0] == '[' && txt[len(txt)-1] == ']' {
+			section := strings.TrimSpace(txt[1 : len(txt)-1])
+			target = nil
+			for _, c := range s.commands {
+				if c.name == section {
+					target = c.flags
+				}
+			}
+			if target == nil {
+				return fmt.Errorf("%s:%d: unknown command section [%s]", path, line, section)
+			}
+			continue
+		}
+
+		n := strings.Index(txt, "=")
+		if n < 0 {
+			return fmt.Errorf("%s:%d: expected 'name = value'", path, line)
+		}
+		name := strings.TrimSpace(txt[:n])
+		value, v_err := configValue(strings.TrimSpace(txt[n+1:]))
+		if v_err != nil {
+			return fmt.Errorf("%s:%d: %s", path, line, v_err)
+		}
+
+		f := target.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("%s:%d: unknown flag '%s'", path, line, name)
+		}
+		name = target.canonical(f.Name)
+		if target.IsSet(name) {
+			continue
+		}
+		if e := f.Value.Set(value); e != nil {
+			return fmt.Errorf("%s:%d: invalid value %q for '%s': %v", path, line, value, name, e)
+		}
+		target.sources[name] = "file"
+	}
+	return scanner.Err()
+}
+
+// Decodes a config value, removing quotes and trailing comments, arrays are joined for Multi flags.
+func configValue(input string) (string, error) {
+	if len(input) == 0 {
+		return input, nil
+	}
+
+	switch input[0] {
+	case '"':
+		end := closingQuote(input)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", input)
+		}
+		return strconv.Unquote(input[:end+1])
+	case '\'':
+		end := strings.IndexByte(input[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string %s", input)
+		}
+		return input[1 : end+1], nil
+	case '[':
+		end := strings.LastIndexByte(input, ']')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated array %s", input)
+		}
+		var items []string
+		for _, item := range splitArray(input[1:end]) {
+			v, err := configValue(strings.TrimSpace(item))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, v)
+		}
+		return escape_array(items), nil
+	}
+
+	if n := strings.Index(input, " #"); n > -1 {
+		input = strings.TrimSpace(input[:n])
+	}
+	return input, nil
+}
+
+// Returns the index of the quote closing a double quoted string.
+func closingQuote(input string) int {
+	for i := 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// Splits array items on commas outside of quotes.
+func splitArray(input string) (items []string) {
+	var quote byte
+	start := 0
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, input[start:i])
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(input[start:]); last != "" {
+		items = append(items, last)
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package eflag
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Counts occurrences of a flag, ie.. -vvv
+type countValue struct {
+	value *int
+}
+
+func (C *countValue) String() string {
+	if C.value == nil {
+		return "0"
+	}
+	return strconv.Itoa(*C.value)
+}
+
+// Each occurrence increments the count, an explicit number sets it, ie.. --verbose=2
+func (C *countValue) Set(value string) error {
+	switch value {
+	case "true":
+		*C.value++
+	case "false":
+		*C.value = 0
+	default:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("expected a count")
+		}
+		*C.value = n
+	}
+	return nil
+}
+
+func (C *countValue) Get() interface{} { return *C.value }
+
+func (C *countValue) IsBoolFlag() bool { return true }
+
+// Returns true if flag takes no value, ie.. Bool or Count.
+func isBoolFlag(f *Flag) bool {
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+		return b.IsBoolFlag()
+	}
+	return false
+}
+
+// Count variable, incremented for each occurrence, ie.. -vvv is 3
+func (E *EFlagSet) Count(name string, usage string) *int {
+	output := new(int)
+	E.CountVar(output, name, usage)
+	return output
+}
+
+// Count variable, incremented for each occurrence, ie.. -vvv is 3
+func (E *EFlagSet) CountVar(p *int, name string, usage string) {
+	if len(usage) > 0 {
+		usage = fmt.Sprintf("%s (repeatable)", usage)
+	}
+	E.Var(&countValue{p}, name, usage)
+}
+
+var (
+	Count    = cmd.Count
+	CountVar = cmd.CountVar
+)
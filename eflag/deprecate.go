@@ -0,0 +1,53 @@
+package eflag
+
+import (
+	"fmt"
+	"io"
+)
+
+// Old flag name replaced by a new one.
+type deprecation struct {
+	name    string
+	message string
+}
+
+// Keeps old_name working as a deprecated name for flag new_name, a warning with message is shown when it is used.
+// Deprecated names are hidden from usage unless ShowDeprecated is set.
+func (s *EFlagSet) Deprecate(old_name, new_name, message string) {
+	flag := s.Lookup(new_name)
+	if flag == nil {
+		return
+	}
+	s.Var(flag.Value, old_name, "")
+	s.deprecated[old_name] = deprecation{new_name, message}
+}
+
+// Resolves aliases and deprecated names to the flag's name.
+func (s *EFlagSet) canonical(name string) string {
+	if d, ok := s.deprecated[name]; ok {
+		return d.name
+	}
+	return s.ResolveAlias(name)
+}
+
+// Warns when a deprecated flag name was used.
+func (s *EFlagSet) warnDeprecated(f *Flag) {
+	d, ok := s.deprecated[f.Name]
+	if !ok {
+		return
+	}
+	if d.message != "" {
+		fmt.Fprintf(s.out, "Warning: %s is deprecated, use %s instead. %s\n", flagName(f.Name), flagName(d.name), d.message)
+	} else {
+		fmt.Fprintf(s.out, "Warning: %s is deprecated, use %s instead.\n", flagName(f.Name), flagName(d.name))
+	}
+}
+
+// Lists deprecated flag names for usage.
+func (s *EFlagSet) printDeprecated(output io.Writer) {
+	s.FlagSet.VisitAll(func(f *Flag) {
+		if d, ok := s.deprecated[f.Name]; ok {
+			fmt.Fprintf(output, "  %s\t(deprecated) Use %s instead.\n", flagName(f.Name), flagName(d.name))
+		}
+	})
+}
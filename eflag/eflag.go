@@ -179,36 +179,44 @@ func (E *EFlagSet) InlineArgs(name ...string) {
 
 // A EFlagSet is a set of defined flags.
 type EFlagSet struct {
-	name          string
-	Header        string // Header presented at start of help.
-	Footer        string // Footer presented at end of help.
-	AdaptArgs     bool   // Reorders flags and arguments so flags come first, non-flag arguments second, unescapes arguments with '\' escape character.
-	ShowSyntax    bool   // Display Usage: line, InlineArgs will automatically display usage info.
-	alias         map[string]string
-	out           io.Writer
-	errorHandling ErrorHandling
-	setFlags      []string
-	order         []string
-	argMap        []*flag.Flag
-	syntaxName    string
+	name           string
+	Header         string // Header presented at start of help.
+	Footer         string // Footer presented at end of help.
+	AdaptArgs      bool   // Same as SetInterspersed(true), and unescapes arguments with '\' escape character.
+	ShowSyntax     bool   // Display Usage: line, InlineArgs will automatically display usage info.
+	EnvPrefix      string // Flags not set on the command line fall back to environment variable PREFIX_NAME, ie.. TOOL_OUT_FILE for --out-file.
+	ShowDeprecated bool   // List deprecated flag names in usage.
+	FileValues     bool   // Flag values starting with '@' are read from the named file, '@-' reads stdin and '@@' escapes a literal '@'.
+	PassUnknown    bool   // Unrecognized flags are collected into UnknownArgs() rather than causing an error, with the next argument as their value unless it starts with '-' or the value is attached with '='.
+	AllowPrefix    bool   // Accept unambiguous prefixes of flag names, ie.. --time for --timeout.
+	PromptMissing  bool   // When stdin is a terminal, prompt for required flags which were not set rather than failing, see Secret.
+	alias          map[string]string
+	aliases        map[string][]string
+	out            io.Writer
+	errorHandling  ErrorHandling
+	setFlags       []string
+	order          []string
+	argMap         []*flag.Flag
+	syntaxName     string
+	commands       []*command
+	selected       *command
+	env            map[string]string
+	sources        map[string]string
+	config_file    string
+	required       []string
+	depends        []dependency
+	deprecated     map[string]deprecation
+	validators     []validator
+	interspersed   bool
+	unknown        []string
+	groups         []group
+	secrets        map[string]struct{}
+	post_parse     []func(flags *EFlagSet) error
+	lazy           []lazyDefault
 	*flag.FlagSet
 }
 
-var cmd = EFlagSet{
-	os.Args[0],
-	"",
-	"",
-	false,
-	false,
-	make(map[string]string),
-	os.Stderr,
-	ExitOnError,
-	make([]string, 0),
-	make([]string, 0),
-	make([]*flag.Flag, 0),
-	os.Args[0],
-	flag.NewFlagSet(os.Args[0], flag.ContinueOnError),
-}
+var cmd = NewFlagSet(os.Args[0], ExitOnError)
 
 var (
 	InlineArgs    = cmd.InlineArgs
@@ -216,6 +224,7 @@ var (
 	SetOutput     = cmd.SetOutput
 	PrintDefaults = cmd.PrintDefaults
 	Shorten       = cmd.Shorten
+	Alias         = cmd.Alias
 	String        = cmd.String
 	StringVar     = cmd.StringVar
 	Arg           = cmd.Arg
@@ -233,6 +242,8 @@ var (
 	Lookup        = cmd.Lookup
 	Multi         = cmd.Multi
 	MultiVar      = cmd.MultiVar
+	Enum          = cmd.Enum
+	EnumVar       = cmd.EnumVar
 	NArg          = cmd.NArg
 	NFlag         = cmd.NFlag
 	Name          = cmd.Name
@@ -243,6 +254,15 @@ var (
 	Uint64        = cmd.Uint64
 	Uint64Var     = cmd.Uint64Var
 	Var           = cmd.Var
+	Command       = cmd.Command
+	Subcommand    = cmd.Subcommand
+	Env           = cmd.Env
+	ConfigFile    = cmd.ConfigFile
+	ParseFile     = cmd.ParseFile
+	Require       = cmd.Require
+	Requires      = cmd.Requires
+	Deprecate     = cmd.Deprecate
+	Source        = cmd.Source
 	Visit         = cmd.Visit
 	VisitAll      = cmd.VisitAll
 )
@@ -257,6 +277,16 @@ func Footer(input string) {
 	cmd.Footer = input
 }
 
+// Sets the environment variable prefix for flags.
+func EnvPrefix(prefix string) {
+	cmd.EnvPrefix = prefix
+}
+
+// Enables reading flag values from files, ie.. --list=@values.txt
+func FileValues(enable bool) {
+	cmd.FileValues = enable
+}
+
 // Parse flags
 func Parse() (err error) {
 	if len(os.Args) > 1 {
@@ -280,6 +310,14 @@ func (s *EFlagSet) Order(name ...string) {
 	}
 }
 
+// Allows flags to appear after arguments, ie.. 'tool file --verbose', otherwise parsing stops at the first argument.
+// Parsing always stops at '--', arguments after it are passed through as is.
+func (s *EFlagSet) SetInterspersed(interspersed bool) {
+	s.interspersed = interspersed
+}
+
+var SetInterspersed = cmd.SetInterspersed
+
 // Returns extra arguments.
 func (s *EFlagSet) Args() []string {
 	args := s.FlagSet.Args()
@@ -301,19 +339,20 @@ func (s *EFlagSet) SetOutput(output io.Writer) {
 // Load a flag created with flag package.
 func NewFlagSet(name string, errorHandling ErrorHandling) (output *EFlagSet) {
 	output = &EFlagSet{
-		name,
-		"",
-		"",
-		false,
-		false,
-		make(map[string]string),
-		os.Stderr,
-		errorHandling,
-		make([]string, 0),
-		make([]string, 0),
-		make([]*flag.Flag, 0),
-		name,
-		flag.NewFlagSet(name, flag.ContinueOnError),
+		name:          name,
+		alias:         make(map[string]string),
+		aliases:       make(map[string][]string),
+		env:           make(map[string]string),
+		sources:       make(map[string]string),
+		deprecated:    make(map[string]deprecation),
+		secrets:       make(map[string]struct{}),
+		out:           os.Stderr,
+		errorHandling: errorHandling,
+		setFlags:      make([]string, 0),
+		order:         make([]string, 0),
+		argMap:        make([]*flag.Flag, 0),
+		syntaxName:    name,
+		FlagSet:       flag.NewFlagSet(name, flag.ContinueOnError),
 	}
 	output.Usage = func() {
 		output.Parse([]string{"--help"})
@@ -368,16 +407,16 @@ func (s *EFlagSet) PrintDefaults() {
 		}
 		var text []string
 		name := flag.Name
-		alias := s.alias[flag.Name]
-		if alias != "" {
-			if len(alias) > 1 {
-				text = append(text, fmt.Sprintf("  --%s,", alias))
+		aliases := s.Aliases(flag.Name)
+		for i, alias := range aliases {
+			if i == 0 {
+				text = append(text, fmt.Sprintf("  %s,", flagName(alias)))
 			} else {
-				text = append(text, fmt.Sprintf("  -%s,", alias))
+				text = append(text, fmt.Sprintf(" %s,", flagName(alias)))
 			}
 		}
 		space := " "
-		if alias == "" {
+		if len(aliases) == 0 {
 			space = "  "
 		}
 		if len(name) > 1 {
@@ -386,28 +425,33 @@ func (s *EFlagSet) PrintDefaults() {
 			text = append(text, fmt.Sprintf("%s-%s", space, name))
 		}
 
-		switch flag.DefValue[0] {
-		case '"':
+		switch {
+		case flag.DefValue == "":
+		case flag.DefValue[0] == '"':
 			if strings.HasPrefix(flag.DefValue, "\"<") && strings.HasSuffix(flag.DefValue, ">\"") {
 				text = append(text, fmt.Sprintf("=%q", flag.DefValue[2:len(flag.DefValue)-2]))
 			} else {
 				text = append(text, fmt.Sprintf("=%s", flag.DefValue))
 			}
-		case '<':
+		case flag.DefValue[0] == '<':
 			if flag.DefValue[len(flag.DefValue)-1] == '>' {
 				text = append(text, fmt.Sprintf("=%q", flag.DefValue[1:len(flag.DefValue)-1]))
 			} else {
 				text = append(text, fmt.Sprintf("=%s", flag.DefValue))
 			}
 		default:
-			if flag.DefValue != "true" && flag.DefValue != "false" {
+			if flag.DefValue != "true" && flag.DefValue != "false" && !isBoolFlag(flag) {
 				text = append(text, fmt.Sprintf("=%s", flag.DefValue))
 			}
 		}
 
-		text = append(text, fmt.Sprintf("\t%s\n", flag.Usage))
+		if env := s.envName(flag.Name); env != "" {
+			text = append(text, fmt.Sprintf("\t%s (env: %s)\n", flag.Usage, env))
+		} else {
+			text = append(text, fmt.Sprintf("\t%s\n", flag.Usage))
+		}
 
-		if alias == "" {
+		if len(aliases) == 0 {
 			flag_text[name] = strings.Join(text[0:], "")
 			flag_order = append(flag_order, name)
 		} else {
@@ -419,6 +463,17 @@ func (s *EFlagSet) PrintDefaults() {
 	// Place Aliases first.
 	flag_order = append(alias_order, flag_order[0:]...)
 
+	// Hold back grouped flags, they are listed under their titles.
+	grouped := make(map[string]string)
+	for _, g := range s.groups {
+		for _, name := range g.names {
+			if txt, ok := flag_text[name]; ok {
+				grouped[name] = txt
+				delete(flag_text, name)
+			}
+		}
+	}
+
 	//OutterLoop:
 	for _, v := range flag_order {
 		for _, o := range s.order {
@@ -439,21 +494,59 @@ func (s *EFlagSet) PrintDefaults() {
 		}
 	}
 
+	if s.ShowDeprecated {
+		s.printDeprecated(output)
+	}
+
 	fmt.Fprintf(output, "  --help\tDisplays this usage information.\n")
+
+	for _, g := range s.groups {
+		fmt.Fprintf(output, "\n%s:\n", g.title)
+		for _, name := range g.names {
+			if txt, ok := grouped[name]; ok {
+				fmt.Fprint(output, txt)
+			}
+		}
+	}
 	output.Flush()
 }
 
 // Adds a single charachter alias to the command, ie.. --help h
 func (s *EFlagSet) Shorten(name string, ch rune) {
+	s.Alias(name, string(ch))
+}
+
+// Adds aliases to the flag, single character or long names, ie.. Alias("destination", "dest", "d")
+func (s *EFlagSet) Alias(name string, aliases ...string) {
 	flag := s.Lookup(name)
 	if flag == nil {
 		return
 	}
-	s.Var(flag.Value, string(ch), "")
-	s.alias[name] = string(ch)
+	for _, alias := range aliases {
+		if s.Lookup(alias) != nil {
+			continue
+		}
+		s.Var(flag.Value, alias, "")
+		s.aliases[name] = append(s.aliases[name], alias)
+
+		// Create reverse lookup
+		s.alias[fmt.Sprintf("-%s-", alias)] = name
+	}
+}
 
-	// Create reverse lookup
-	s.alias[fmt.Sprintf("-%s-", string(ch))] = name
+// Returns the aliases of flag name, single character aliases first.
+func (s *EFlagSet) Aliases(name string) (aliases []string) {
+	for _, a := range s.aliases[name] {
+		if len(a) == 1 {
+			aliases = append(aliases, a)
+		}
+	}
+	for _, a := range s.aliases[name] {
+		if len(a) > 1 {
+			aliases = append(aliases, a)
+		}
+	}
+	return
 }
 
 // Resolves Alias name to fullname
@@ -465,6 +558,8 @@ func (s *EFlagSet) ResolveAlias(name string) string {
 	}
 }
 
+// Returns true if flag was set on the command line or from the environment, values from a config file are reported by Source.
+// A bool flag turned off with --no-<name> reports both name and no-<name> as set.
 func (s *EFlagSet) IsSet(name string) bool {
 	for _, k := range s.setFlags {
 		if k == name {
@@ -475,47 +570,101 @@ func (s *EFlagSet) IsSet(name string) bool {
 }
 
 // Wraps around the standard flag Parse, adds header and footer.
+// When subcommands are registered, flags up to the command name are parsed here and the rest by the subcommand.
 func (s *EFlagSet) Parse(args []string) (err error) {
+	if len(s.commands) > 0 {
+		return s.parseCommand(args)
+	}
+	return s.parse(args)
+}
+
+// Parses args against the flags of this set.
+func (s *EFlagSet) parse(args []string) (err error) {
 	// set usage to empty to prevent unessisary work as we dump the output of flag.
 	s.Usage = func() {}
 
 	var (
-		tmp      []string
-		trailing []string
+		flags      []string
+		positional []string
+		negated    []string
+		prefix_err error
 	)
 
-	// Split bool flags so that '-abc' becomes '-a -b -c' before being parsed.
-	for _, a := range args {
-		if !strings.HasPrefix(a, "-") {
-			if !s.AdaptArgs {
-				tmp = append(tmp, a)
-			} else {
-				trailing = append(trailing, a)
+	interspersed := s.interspersed || s.AdaptArgs
+	s.unknown = nil
+
+	// Split bool flags so that '-abc' becomes '-a -b -c', and gather flags with their values ahead of arguments.
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			if !interspersed {
+				positional = append(positional, args[i:]...)
+				break
 			}
+			positional = append(positional, a)
 			continue
 		}
-		if strings.HasPrefix(a, "--") {
-			tmp = append(tmp, a)
+		if s.AllowPrefix && strings.HasPrefix(a, "--") {
+			if a, prefix_err = s.expandPrefix(a); prefix_err != nil {
+				break
+			}
+		}
+		if s.PassUnknown && s.isUnknown(a) {
+			s.unknown = append(s.unknown, a)
+			if s.unknownValue(a, args[i+1:]) {
+				i++
+				s.unknown = append(s.unknown, args[i])
+			}
 			continue
 		}
 		if strings.Contains(a, "=") {
-			tmp = append(tmp, a)
+			flags = append(flags, a)
 			continue
 		}
-		a = strings.TrimPrefix(a, "-")
-		if len(a) == 0 {
-			continue
-
+		var name string
+		if strings.HasPrefix(a, "--") {
+			if n, ok := s.negate(a); ok {
+				negated = append(negated, n)
+				flags = append(flags, fmt.Sprintf("--%s=false", n))
+				continue
+			}
+			flags = append(flags, a)
+			name = a[2:]
+		} else {
+			for _, ch := range a[1:] {
+				flags = append(flags, fmt.Sprintf("-%c", ch))
+				name = string(ch)
+			}
 		}
-		tmp = append(tmp, fmt.Sprintf("-%c", a[0]))
-		for _, ch := range a[1:] {
-			tmp = append(tmp, fmt.Sprintf("-%c", ch))
+		// Flags that take a value consume the next argument.
+		if f := s.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
 		}
 	}
 
-	args = tmp[0:]
-	if s.AdaptArgs {
-		args = append(args, trailing[0:]...)
+	// Terminate flags, so arguments are never mistaken for flags.
+	args = flags
+	if len(positional) > 0 {
+		args = append(append(args, "--"), positional...)
+	}
+
+	// Load config file values first, so the command line overrides them.
+	var file_err error
+	if s.config_file != "" {
+		if _, e := os.Stat(s.config_file); e == nil {
+			file_err = s.ParseFile(s.config_file)
+		}
+	}
+
+	// Read values from files, ie.. --list=@values.txt
+	var expand_err error
+	if s.FileValues {
+		args, expand_err = s.expandFiles(args)
 	}
 
 	// Remove normal error message printing.
@@ -528,6 +677,30 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 	err = s.FlagSet.Parse(args)
 	s.out = stdOut
 
+	// Reconstruct error message with the argument as given.
+	var errStr string
+	if err != nil && err != flag.ErrHelp {
+		errStr = err.Error()
+		cmd := strings.Split(errStr, "-")
+		if len(cmd) > 1 {
+			for _, arg := range args {
+				if strings.Contains(arg, cmd[1]) {
+					err = fmt.Errorf("%s%s", cmd[0], arg)
+					break
+				}
+			}
+		}
+	}
+	if err == nil {
+		err = prefix_err
+	}
+	if err == nil {
+		err = expand_err
+	}
+	if err == nil {
+		err = file_err
+	}
+
 	val_map := make(map[string]*flag.Value)
 
 	// Remove example text from strings, ie.. <server to connect with>
@@ -543,6 +716,11 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 
 	mark_set_flags := func(f *flag.Flag) {
 		s.setFlags = append(s.setFlags, f.Name)
+		s.sources[f.Name] = "flag"
+		if name := s.canonical(f.Name); name != f.Name {
+			s.setFlags = append(s.setFlags, name)
+			s.sources[name] = "flag"
+		}
 	}
 
 	num := 0
@@ -596,6 +774,34 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 	}
 
 	s.FlagSet.Visit(mark_set_flags)
+	for _, name := range negated {
+		s.setFlags = append(s.setFlags, "no-"+name)
+		s.sources["no-"+name] = "flag"
+	}
+	if err == nil {
+		s.FlagSet.Visit(s.warnDeprecated)
+	}
+
+	// Fall back to environment variables for flags not set on the command line.
+	if err == nil {
+		err = s.applyEnv()
+	}
+	if err == nil {
+		err = s.applyLazy()
+	}
+	if err == nil {
+		s.promptRequired()
+		err = s.checkRequired()
+	}
+	if err == nil {
+		err = s.checkDepends()
+	}
+	if err == nil {
+		err = s.checkValid()
+	}
+	if err == nil {
+		err = s.runPostParse()
+	}
 
 	// Implement new Usage function.
 	s.Usage = func() {
@@ -621,7 +827,9 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 			}
 			fmt.Fprintf(s.out, "Options:\n")
 		} else {
-			if len(arg_names) > 0 {
+			if len(s.commands) > 0 {
+				fmt.Fprintf(s.out, "Usage: %s [options] <command> [command options]\n\n", s.syntaxName)
+			} else if len(arg_names) > 0 {
 				fmt.Fprintf(s.out, "Usage: %s [options] %s\n\n", s.syntaxName, strings.Join(arg_names, " "))
 			} else if s.ShowSyntax {
 				fmt.Fprintf(s.out, "Usage: %s [options]\n\n", s.syntaxName)
@@ -632,6 +840,7 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 			fmt.Fprintf(s.out, "Available '%s' options:\n", s.name)
 		}
 		s.PrintDefaults()
+		s.printCommands()
 		if s.Footer != "" {
 			fmt.Fprintf(s.out, "%s\n", s.Footer)
 		}
@@ -639,37 +848,15 @@ func (s *EFlagSet) Parse(args []string) (err error) {
 
 	// Implement a new error message.
 	if err != nil {
-		if err != flag.ErrHelp {
-			errStr := err.Error()
-			cmd := strings.Split(errStr, "-")
-			if len(cmd) > 1 {
-				for _, arg := range args {
-					if strings.Contains(arg, cmd[1]) {
-						err = fmt.Errorf("%s%s", cmd[0], arg)
-						if s.errorHandling != ReturnErrorOnly {
-							fmt.Fprintf(s.out, "%s\n\n", errStr)
-						}
-						break
-					}
-				}
-			} else {
-				if s.errorHandling != ReturnErrorOnly {
-					fmt.Fprintf(s.out, "%s\n\n", errStr)
-				}
+		if err != flag.ErrHelp && s.errorHandling != ReturnErrorOnly {
+			if errStr == "" {
+				errStr = err.Error()
 			}
+			fmt.Fprintf(s.out, "%s\n\n", errStr)
 		}
 
 		// Errorflag handling.
-		switch s.errorHandling {
-		case ReturnErrorOnly:
-		case ContinueOnError:
-			s.Usage()
-		case ExitOnError:
-			s.Usage()
-			os.Exit(2)
-		case PanicOnError:
-			panic(err)
-		}
+		s.handleError(err)
 	}
 	return
 }
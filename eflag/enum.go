@@ -0,0 +1,55 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+type enumValue struct {
+	value   *string
+	choices []string
+}
+
+func (E *enumValue) String() string {
+	if E.value == nil {
+		return ""
+	}
+	return *E.value
+}
+
+// Accepts only one of the choices, matched without regard to case.
+func (E *enumValue) Set(value string) error {
+	for _, c := range E.choices {
+		if strings.EqualFold(c, value) {
+			*E.value = c
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of: %s", strings.Join(E.choices, ", "))
+}
+
+func (E *enumValue) Get() interface{} { return *E.value }
+
+func (E *enumValue) zero() { *E.value = "" }
+
+// Enum variable, value must be one of choices, ie.. --format=json
+func (E *EFlagSet) Enum(name string, value string, choices []string, usage string) *string {
+	output := new(string)
+	E.EnumVar(output, name, value, choices, usage)
+	return output
+}
+
+// Enum variable, value must be one of choices, ie.. --format=json
+func (E *EFlagSet) EnumVar(p *string, name string, value string, choices []string, usage string) {
+	*p = value
+
+	v := enumValue{
+		value:   p,
+		choices: choices,
+	}
+
+	if len(usage) > 0 {
+		usage = fmt.Sprintf("%s (choices: %s)", usage, strings.Join(choices, ", "))
+	}
+	E.Var(&v, name, usage)
+}
@@ -0,0 +1,55 @@
+package eflag
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Binds flag name to environment variable, used when the flag is not set on the command line.
+func (s *EFlagSet) Env(name string, variable string) {
+	s.env[name] = variable
+}
+
+// Returns the environment variable for flag name, from Env or EnvPrefix.
+func (s *EFlagSet) envName(name string) string {
+	if v, ok := s.env[name]; ok {
+		return v
+	}
+	if s.EnvPrefix == "" {
+		return ""
+	}
+	// Skip aliases created by Shorten.
+	if f := s.Lookup(name); f == nil || f.Usage == "" {
+		return ""
+	}
+	name = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	return fmt.Sprintf("%s_%s", strings.TrimSuffix(s.EnvPrefix, "_"), name)
+}
+
+// Sets flags not already set from their environment variables.
+func (s *EFlagSet) applyEnv() (err error) {
+	if len(s.env) == 0 && s.EnvPrefix == "" {
+		return nil
+	}
+	s.FlagSet.VisitAll(func(f *Flag) {
+		if err != nil || s.IsSet(f.Name) {
+			return
+		}
+		env := s.envName(f.Name)
+		if env == "" {
+			return
+		}
+		val, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+		if e := f.Value.Set(val); e != nil {
+			err = fmt.Errorf("invalid value %q in environment variable %s: %v", val, env, e)
+			return
+		}
+		s.setFlags = append(s.setFlags, f.Name)
+		s.sources[f.Name] = "env"
+	})
+	return
+}
@@ -0,0 +1,199 @@
+package eflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flag as presented in generated documentation.
+type docFlag struct {
+	names string
+	value string
+	usage string
+}
+
+// Collects flags in display order, aliased flags first, skipping hidden flags and inline arguments.
+func (s *EFlagSet) docFlags() (flags []docFlag) {
+	var plain []docFlag
+
+	args := make(map[string]struct{})
+	for _, v := range s.argMap {
+		args[v.Name] = struct{}{}
+	}
+
+	s.VisitAll(func(f *Flag) {
+		if _, ok := args[f.Name]; ok || f.Usage == "" {
+			return
+		}
+		d := docFlag{flagName(f.Name), defaultText(f), f.Usage}
+		if aliases := s.Aliases(f.Name); len(aliases) > 0 {
+			var names []string
+			for _, alias := range aliases {
+				names = append(names, flagName(alias))
+			}
+			d.names = fmt.Sprintf("%s, %s", strings.Join(names, ", "), d.names)
+			flags = append(flags, d)
+		} else {
+			plain = append(plain, d)
+		}
+	})
+	return append(flags, plain...)
+}
+
+// Returns the default value of f for display, empty for bool flags.
+func defaultText(f *Flag) string {
+	def := f.DefValue
+	if def == "" || isBoolFlag(f) {
+		return ""
+	}
+	if strings.HasPrefix(def, "\"<") && strings.HasSuffix(def, ">\"") {
+		return def[2 : len(def)-2]
+	}
+	if strings.HasPrefix(def, "<") && strings.HasSuffix(def, ">") {
+		return def[1 : len(def)-1]
+	}
+	return def
+}
+
+// Returns the inline argument names for the syntax line.
+func (s *EFlagSet) docArgs() (names []string) {
+	has_multi := false
+	for _, f := range s.argMap {
+		name := remove_quotes(f.DefValue)
+		if _, ok := f.Value.(*multiValue); ok && !has_multi {
+			has_multi = true
+			name = name + "..."
+		}
+		names = append(names, name)
+	}
+	return
+}
+
+// Returns the syntax line.
+func (s *EFlagSet) docSyntax() string {
+	syntax := []string{s.syntaxName, "[options]"}
+	if len(s.commands) > 0 {
+		syntax = append(syntax, "<command>", "[command options]")
+	} else {
+		syntax = append(syntax, s.docArgs()...)
+	}
+	return strings.Join(syntax, " ")
+}
+
+// Escapes text for troff.
+func manEscape(input string) string {
+	input = strings.NewReplacer("\\", "\\e", "-", "\\-").Replace(input)
+	lines := strings.Split(input, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, ".") || strings.HasPrefix(l, "'") {
+			lines[i] = "\\&" + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Returns the date of a generated man page, from SOURCE_DATE_EPOCH when set, so builds are reproducible.
+func manDate() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Now()
+}
+
+// Writes usage as a troff man page, for section 1, dated today or by SOURCE_DATE_EPOCH.
+func (s *EFlagSet) GenManPage(w io.Writer) (err error) {
+	p := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	name := strings.ReplaceAll(s.syntaxName, " ", "-")
+	p(".TH %q 1 %q\n", strings.ToUpper(name), manDate().Format("2006-01-02"))
+	p(".SH NAME\n%s\n", manEscape(name))
+	p(".SH SYNOPSIS\n%s\n", manEscape(s.docSyntax()))
+	if s.Header != "" {
+		p(".SH DESCRIPTION\n%s\n", manEscape(s.Header))
+	}
+	if len(s.argMap) > 0 {
+		p(".SH ARGUMENTS\n")
+		for i, f := range s.argMap {
+			p(".TP\n.B %s\n%s\n", manEscape(s.docArgs()[i]), manEscape(f.Usage))
+		}
+	}
+	p(".SH OPTIONS\n")
+	for _, f := range s.docFlags() {
+		if f.value != "" {
+			p(".TP\n.B %s=%s\n%s\n", manEscape(f.names), manEscape(f.value), manEscape(f.usage))
+		} else {
+			p(".TP\n.B %s\n%s\n", manEscape(f.names), manEscape(f.usage))
+		}
+	}
+	p(".TP\n.B %s\nDisplays usage information.\n", manEscape("--help"))
+	if len(s.commands) > 0 {
+		p(".SH COMMANDS\n")
+		for _, c := range s.commands {
+			p(".TP\n.B %s\n%s\n", manEscape(c.name), manEscape(c.usage))
+		}
+	}
+	if s.Footer != "" {
+		p(".SH NOTES\n%s\n", manEscape(s.Footer))
+	}
+	return
+}
+
+// Escapes text for a markdown table cell.
+func mdCell(input string) string {
+	return strings.NewReplacer("|", "\\|", "\n", "<br>").Replace(input)
+}
+
+// Writes usage as markdown.
+func (s *EFlagSet) GenMarkdown(w io.Writer) (err error) {
+	p := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	p("# %s\n\n", s.syntaxName)
+	if s.Header != "" {
+		p("%s\n\n", s.Header)
+	}
+	p("## Usage\n\n```\n%s\n```\n\n", s.docSyntax())
+	if len(s.argMap) > 0 {
+		p("## Arguments\n\n| Argument | Description |\n| --- | --- |\n")
+		for i, f := range s.argMap {
+			p("| `%s` | %s |\n", s.docArgs()[i], mdCell(f.Usage))
+		}
+		p("\n")
+	}
+	p("## Options\n\n| Flag | Default | Description |\n| --- | --- | --- |\n")
+	for _, f := range s.docFlags() {
+		value := ""
+		if f.value != "" {
+			value = fmt.Sprintf("`%s`", f.value)
+		}
+		p("| `%s` | %s | %s |\n", f.names, value, mdCell(f.usage))
+	}
+	p("| `--help` | | Displays usage information. |\n\n")
+	if len(s.commands) > 0 {
+		p("## Commands\n\n| Command | Description |\n| --- | --- |\n")
+		for _, c := range s.commands {
+			p("| `%s` | %s |\n", c.name, mdCell(c.usage))
+		}
+		p("\n")
+	}
+	if s.Footer != "" {
+		p("%s\n", s.Footer)
+	}
+	return
+}
+
+var (
+	GenManPage  = cmd.GenManPage
+	GenMarkdown = cmd.GenMarkdown
+)
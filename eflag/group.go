@@ -0,0 +1,21 @@
+package eflag
+
+// Titled section of flags in usage.
+type group struct {
+	title string
+	names []string
+}
+
+// Lists the named flags under title in usage, ie.. Group("Connection", "server", "port", "tls")
+// Calling Group again with the same title adds to that section.
+func (s *EFlagSet) Group(title string, name ...string) {
+	for i, g := range s.groups {
+		if g.title == title {
+			s.groups[i].names = append(g.names, name...)
+			return
+		}
+	}
+	s.groups = append(s.groups, group{title, name})
+}
+
+var Group = cmd.Group
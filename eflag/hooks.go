@@ -0,0 +1,19 @@
+package eflag
+
+// Registers fn to run after a successful Parse, in the order registered, for normalizing values or deriving one flag from another.
+// An error from fn is reported as a parse error, along with usage.
+func (s *EFlagSet) RegisterPostParse(fn func(flags *EFlagSet) error) {
+	s.post_parse = append(s.post_parse, fn)
+}
+
+// Runs post parse callbacks.
+func (s *EFlagSet) runPostParse() error {
+	for _, fn := range s.post_parse {
+		if err := fn(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var RegisterPostParse = cmd.RegisterPostParse
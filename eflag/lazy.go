@@ -0,0 +1,41 @@
+package eflag
+
+import "fmt"
+
+// Default computed during Parse.
+type lazyDefault struct {
+	name string
+	def  string // Default of the flag before placeholder replaced it, restored by Reset.
+	fn   func() string
+}
+
+// Computes the default of flag name with fn during Parse, when the flag was not otherwise set, ie.. a home directory.
+// Usage shows placeholder rather than the computed value.
+func (s *EFlagSet) LazyDefault(name string, placeholder string, fn func() string) {
+	f := s.Lookup(name)
+	if f == nil {
+		return
+	}
+	s.lazy = append(s.lazy, lazyDefault{name, f.DefValue, fn})
+	f.DefValue = fmt.Sprintf("<%s>", placeholder)
+}
+
+// Applies lazy defaults to flags which were not set.
+func (s *EFlagSet) applyLazy() error {
+	for _, l := range s.lazy {
+		if s.Source(l.name) != "default" {
+			continue
+		}
+		f := s.Lookup(l.name)
+		if f == nil {
+			continue
+		}
+		value := l.fn()
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid default %q for %s: %s", value, flagName(l.name), err)
+		}
+	}
+	return nil
+}
+
+var LazyDefault = cmd.LazyDefault
@@ -0,0 +1,54 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Imports the flags of other, with their aliases, ordering, groups, and settings of Require, Requires, Env, Deprecate, Secret, Validate, LazyDefault and RegisterPostParse.
+// RegisterPostParse hooks of other are called with s. Positional Args, subcommands and set wide fields such as Header or EnvPrefix are not imported.
+// Returns an error, importing nothing, if any flag of other is already defined.
+func (s *EFlagSet) AddFlagSet(other *EFlagSet) error {
+	var collisions []string
+	other.FlagSet.VisitAll(func(f *Flag) {
+		if s.Lookup(f.Name) != nil {
+			collisions = append(collisions, flagName(f.Name))
+		}
+	})
+	if len(collisions) > 0 {
+		return fmt.Errorf("flag redefined: %s", strings.Join(collisions, ", "))
+	}
+
+	other.FlagSet.VisitAll(func(f *Flag) {
+		s.Var(f.Value, f.Name, f.Usage)
+		s.Lookup(f.Name).DefValue = f.DefValue
+	})
+
+	for k, v := range other.alias {
+		s.alias[k] = v
+	}
+	for k, v := range other.aliases {
+		s.aliases[k] = append(s.aliases[k], v...)
+	}
+	for k, v := range other.env {
+		s.env[k] = v
+	}
+	for k, v := range other.deprecated {
+		s.deprecated[k] = v
+	}
+	for k, v := range other.secrets {
+		s.secrets[k] = v
+	}
+	s.order = append(s.order, other.order...)
+	s.required = append(s.required, other.required...)
+	s.depends = append(s.depends, other.depends...)
+	s.validators = append(s.validators, other.validators...)
+	s.lazy = append(s.lazy, other.lazy...)
+	s.post_parse = append(s.post_parse, other.post_parse...)
+	for _, g := range other.groups {
+		s.Group(g.title, g.names...)
+	}
+	return nil
+}
+
+var AddFlagSet = cmd.AddFlagSet
@@ -0,0 +1,18 @@
+package eflag
+
+import "strings"
+
+// Resolves --no-<name> to bool flag name, unless a flag is defined with the full name.
+func (E *EFlagSet) negate(arg string) (name string, ok bool) {
+	if !strings.HasPrefix(arg, "--no-") || strings.Contains(arg, "=") {
+		return "", false
+	}
+	if E.Lookup(arg[2:]) != nil {
+		return "", false
+	}
+	name = E.canonical(arg[5:])
+	if f := E.Lookup(name); f != nil && isBoolFlag(f) {
+		return name, true
+	}
+	return "", false
+}
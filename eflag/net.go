@@ -0,0 +1,148 @@
+package eflag
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+type ipValue struct {
+	value *net.IP
+}
+
+func (I *ipValue) String() string {
+	if I.value == nil || *I.value == nil {
+		return ""
+	}
+	return I.value.String()
+}
+
+func (I *ipValue) Set(value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("expected an IP address")
+	}
+	*I.value = ip
+	return nil
+}
+
+func (I *ipValue) Get() interface{} { return *I.value }
+
+func (I *ipValue) zero() { *I.value = nil }
+
+type cidrValue struct {
+	value *net.IPNet
+}
+
+func (C *cidrValue) String() string {
+	if C.value == nil || C.value.IP == nil {
+		return ""
+	}
+	return C.value.String()
+}
+
+func (C *cidrValue) Set(value string) error {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("expected a network in CIDR notation, ie.. 10.0.0.0/8")
+	}
+	*C.value = *network
+	return nil
+}
+
+func (C *cidrValue) Get() interface{} { return C.value }
+
+func (C *cidrValue) zero() { *C.value = net.IPNet{} }
+
+type urlValue struct {
+	value *url.URL
+}
+
+func (U *urlValue) String() string {
+	if U.value == nil {
+		return ""
+	}
+	return U.value.String()
+}
+
+// Requires an absolute URL with scheme and host.
+func (U *urlValue) Set(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("expected an absolute URL, ie.. https://example.com/path")
+	}
+	*U.value = *u
+	return nil
+}
+
+func (U *urlValue) Get() interface{} { return U.value }
+
+func (U *urlValue) zero() { *U.value = url.URL{} }
+
+// Sets value as the default of flag name, panicking if it is invalid, as flag does for a redefined flag.
+func mustDefault(v flag.Value, name string, value string) {
+	if err := v.Set(value); err != nil {
+		panic(fmt.Sprintf("invalid default %q for %s: %s", value, flagName(name), err))
+	}
+}
+
+// IP address variable, ie.. --bind=127.0.0.1
+func (E *EFlagSet) IP(name string, value string, usage string) *net.IP {
+	output := new(net.IP)
+	E.IPVar(output, name, value, usage)
+	return output
+}
+
+// IP address variable, ie.. --bind=127.0.0.1, panics if value is not empty and invalid.
+func (E *EFlagSet) IPVar(p *net.IP, name string, value string, usage string) {
+	v := &ipValue{p}
+	*p = nil
+	if value != "" {
+		mustDefault(v, name, value)
+	}
+	E.Var(v, name, usage)
+}
+
+// Network variable in CIDR notation, ie.. --allow=10.0.0.0/8
+func (E *EFlagSet) CIDR(name string, value string, usage string) *net.IPNet {
+	output := new(net.IPNet)
+	E.CIDRVar(output, name, value, usage)
+	return output
+}
+
+// Network variable in CIDR notation, ie.. --allow=10.0.0.0/8, panics if value is not empty and invalid.
+func (E *EFlagSet) CIDRVar(p *net.IPNet, name string, value string, usage string) {
+	v := &cidrValue{p}
+	*p = net.IPNet{}
+	if value != "" {
+		mustDefault(v, name, value)
+	}
+	E.Var(v, name, usage)
+}
+
+// Absolute URL variable, ie.. --server=https://example.com
+func (E *EFlagSet) URL(name string, value string, usage string) *url.URL {
+	output := new(url.URL)
+	E.URLVar(output, name, value, usage)
+	return output
+}
+
+// Absolute URL variable, ie.. --server=https://example.com, panics if value is not empty and invalid.
+func (E *EFlagSet) URLVar(p *url.URL, name string, value string, usage string) {
+	v := &urlValue{p}
+	*p = url.URL{}
+	if value != "" {
+		mustDefault(v, name, value)
+	}
+	E.Var(v, name, usage)
+}
+
+var (
+	IP      = cmd.IP
+	IPVar   = cmd.IPVar
+	CIDR    = cmd.CIDR
+	CIDRVar = cmd.CIDRVar
+	URL     = cmd.URL
+	URLVar  = cmd.URLVar
+)
@@ -0,0 +1,77 @@
+package eflag
+
+import (
+	"errors"
+	"strings"
+)
+
+// Splits line into arguments as a shell would, honoring single quotes, double quotes and backslash escapes.
+func splitLine(line string) (args []string, err error) {
+	var (
+		arg     strings.Builder
+		in_arg  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			// Within double quotes, backslash only escapes quotes and itself.
+			if quote == '"' && c != '"' && c != '\\' {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+			in_arg = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			in_arg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if in_arg {
+				args = append(args, arg.String())
+				arg.Reset()
+				in_arg = false
+			}
+		default:
+			arg.WriteRune(c)
+			in_arg = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("unexpected end of line after '\\'")
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if in_arg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// Splits line into arguments as a shell would, then parses them, ie.. ParseString(`--name "John Smith" --verbose`)
+func (s *EFlagSet) ParseString(line string) (err error) {
+	args, err := splitLine(line)
+	if err != nil {
+		return err
+	}
+	return s.Parse(args)
+}
+
+var ParseString = cmd.ParseString
@@ -0,0 +1,38 @@
+package eflag
+
+import "strings"
+
+// Returns unrecognized flags from the last Parse, as given, with any separate values following their flag, when PassUnknown is set.
+func (s *EFlagSet) UnknownArgs() []string {
+	return append([]string(nil), s.unknown...)
+}
+
+// Returns true if arg names a flag which is not defined, a group of single character flags is unknown if any of them are.
+func (s *EFlagSet) isUnknown(arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if n := strings.Index(name, "="); n > -1 {
+		name = name[:n]
+	}
+	if name == "help" || name == "h" {
+		return false
+	}
+	if strings.HasPrefix(arg, "--") || strings.Contains(arg, "=") {
+		if _, ok := s.negate(arg); ok {
+			return false
+		}
+		return s.Lookup(name) == nil
+	}
+	for _, ch := range name {
+		if s.Lookup(string(ch)) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the unknown flag arg takes the first of next as its value, since its value was not attached with '='.
+func (s *EFlagSet) unknownValue(arg string, next []string) bool {
+	return !strings.Contains(arg, "=") && len(next) > 0 && !strings.HasPrefix(next[0], "-")
+}
+
+var UnknownArgs = cmd.UnknownArgs
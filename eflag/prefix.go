@@ -0,0 +1,39 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expands arg to the full flag name it is a unique prefix of, returns an error listing candidates if ambiguous.
+func (s *EFlagSet) expandPrefix(arg string) (string, error) {
+	name, value := arg[2:], ""
+	if n := strings.Index(name, "="); n > -1 {
+		name, value = name[:n], name[n:]
+	}
+	if name == "" || name == "help" || s.Lookup(name) != nil {
+		return arg, nil
+	}
+	if _, ok := s.negate(arg); ok {
+		return arg, nil
+	}
+
+	var matches []string
+	s.FlagSet.VisitAll(func(f *Flag) {
+		// Skip hidden names, such as deprecated names.
+		if f.Usage != "" && strings.HasPrefix(f.Name, name) {
+			matches = append(matches, f.Name)
+		}
+	})
+
+	switch len(matches) {
+	case 0:
+		return arg, nil
+	case 1:
+		return "--" + matches[0] + value, nil
+	}
+	for i, m := range matches {
+		matches[i] = flagName(m)
+	}
+	return arg, fmt.Errorf("ambiguous flag %s: matches %s", flagName(name), strings.Join(matches, ", "))
+}
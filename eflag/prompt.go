@@ -0,0 +1,77 @@
+package eflag
+
+import (
+	"bufio"
+	"fmt"
+	"golang.org/x/crypto/ssh/terminal"
+	"os"
+	"strings"
+)
+
+// Asks for the value of a missing required flag, secret is set for flags marked with Secret.
+// An error or empty answer stops prompting, leaving the flag to be reported as missing.
+// Replace it to prompt through another package, ie.. PromptFunc = func(p string, secret bool) (string, error) { if secret { return nfo.GetSecret(p), nil }; return nfo.GetInput(p), nil }
+var PromptFunc = promptTerminal
+
+var stdin_reader = bufio.NewReader(os.Stdin)
+
+// Reads a line from the terminal, without echo if secret.
+func promptTerminal(prompt string, secret bool) (string, error) {
+	fmt.Print(prompt)
+	if secret {
+		resp, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Print("\n")
+		return strings.TrimSpace(string(resp)), err
+	}
+	line, err := stdin_reader.ReadString('\n')
+	if err != nil && line != "" {
+		err = nil
+	}
+	return strings.TrimSpace(line), err
+}
+
+// Marks flags as secret, their values are masked when prompted for.
+func (s *EFlagSet) Secret(name ...string) {
+	for _, n := range name {
+		s.secrets[n] = struct{}{}
+	}
+}
+
+// Prompts for required flags which were not set, when PromptMissing is set and stdin is a terminal.
+// Stops at the first error or empty answer, so checkRequired reports what is still missing.
+func (s *EFlagSet) promptRequired() {
+	if !s.PromptMissing || !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+	for _, name := range s.required {
+		if s.Source(name) != "default" {
+			continue
+		}
+		f := s.Lookup(name)
+		if f == nil {
+			continue
+		}
+
+		_, secret := s.secrets[name]
+		prompt := fmt.Sprintf("%s (%s): ", f.Usage, flagName(name))
+		if f.Usage == "" {
+			prompt = fmt.Sprintf("%s: ", flagName(name))
+		}
+
+		for {
+			value, err := PromptFunc(prompt, secret)
+			if err != nil || value == "" {
+				return
+			}
+			if err := f.Value.Set(value); err != nil {
+				fmt.Fprintf(s.out, "invalid value: %s\n", err)
+				continue
+			}
+			break
+		}
+		s.setFlags = append(s.setFlags, name)
+		s.sources[name] = "prompt"
+	}
+}
+
+var Secret = cmd.Secret
@@ -0,0 +1,67 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Marks flags as required, Parse returns an error listing any that were not set on the command line, environment or config file.
+func (s *EFlagSet) Require(name ...string) {
+	s.required = append(s.required, name...)
+}
+
+// Formats flag name as it appears on the command line.
+func flagName(name string) string {
+	if len(name) > 1 {
+		return "--" + name
+	}
+	return "-" + name
+}
+
+// Checks that all required flags were set.
+func (s *EFlagSet) checkRequired() error {
+	var missing []string
+	for _, name := range s.required {
+		if s.Source(name) == "default" {
+			missing = append(missing, flagName(name))
+		}
+	}
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("missing required flag: %s", missing[0])
+	default:
+		return fmt.Errorf("missing required flags: %s", strings.Join(missing, ", "))
+	}
+}
+
+// Flag which depends on other flags being set.
+type dependency struct {
+	name    string
+	depends []string
+}
+
+// Declares that when flag name is set, the flags it depends on must be set as well, ie.. Requires("password", "user").
+func (s *EFlagSet) Requires(name string, depends_on ...string) {
+	s.depends = append(s.depends, dependency{name, depends_on})
+}
+
+// Checks that flags which are set have their dependencies set.
+func (s *EFlagSet) checkDepends() error {
+	for _, d := range s.depends {
+		if s.Source(d.name) == "default" {
+			continue
+		}
+		var missing []string
+		for _, name := range d.depends {
+			if s.Source(name) == "default" {
+				missing = append(missing, flagName(name))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("%s requires %s", flagName(d.name), strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package eflag
+
+import "flag"
+
+// Values with an empty default which do not accept Set(""), returned to their zero value by Reset.
+type zeroValue interface {
+	zero()
+}
+
+// Clears the results of Parse and restores flag defaults, so the same flags may be parsed again, ie.. in tests or a REPL.
+// Subcommands are reset as well, flags with a LazyDefault have it computed again on the next Parse.
+func (s *EFlagSet) Reset() {
+	fs := flag.NewFlagSet(s.FlagSet.Name(), flag.ContinueOnError)
+	fs.Usage = s.FlagSet.Usage
+
+	defaults := make(map[string]string)
+	for _, l := range s.lazy {
+		if f := s.Lookup(l.name); f != nil {
+			defaults[f.Name] = l.def
+		}
+	}
+
+	s.FlagSet.VisitAll(func(f *Flag) {
+		def, ok := defaults[f.Name]
+		if !ok {
+			def = f.DefValue
+		}
+		if err := f.Value.Set(def); err != nil && def == "" {
+			if z, ok := f.Value.(zeroValue); ok {
+				z.zero()
+			}
+		}
+		fs.Var(f.Value, f.Name, f.Usage)
+		fs.Lookup(f.Name).DefValue = f.DefValue
+	})
+	for i, f := range s.argMap {
+		s.argMap[i] = fs.Lookup(f.Name)
+	}
+	s.FlagSet = fs
+
+	s.setFlags = make([]string, 0)
+	s.sources = make(map[string]string)
+	s.unknown = nil
+	s.selected = nil
+
+	for _, c := range s.commands {
+		c.flags.Reset()
+	}
+}
+
+var Reset = cmd.Reset
@@ -0,0 +1,107 @@
+package eflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Byte size units, K, M, G and T alone are binary, as is the KiB form, while KB form is decimal.
+var sizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kib": 1 << 10,
+	"kb":  1e3,
+	"m":   1 << 20,
+	"mib": 1 << 20,
+	"mb":  1e6,
+	"g":   1 << 30,
+	"gib": 1 << 30,
+	"gb":  1e9,
+	"t":   1 << 40,
+	"tib": 1 << 40,
+	"tb":  1e12,
+}
+
+type sizeValue struct {
+	value *int64
+}
+
+// Formats size with the largest unit dividing it evenly, ie.. 10MiB
+func formatSize(size int64) string {
+	for _, u := range []struct {
+		name string
+		size int64
+	}{
+		{"TiB", 1 << 40}, {"TB", 1e12}, {"GiB", 1 << 30}, {"GB", 1e9}, {"MiB", 1 << 20}, {"MB", 1e6}, {"KiB", 1 << 10}, {"KB", 1e3},
+	} {
+		if size != 0 && size%u.size == 0 {
+			return fmt.Sprintf("%d%s", size/u.size, u.name)
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
+
+// Parses a size such as 512K, 10MB, or 1.5GiB into bytes.
+func parseSize(input string) (int64, error) {
+	input = strings.TrimSpace(input)
+	n := strings.IndexFunc(input, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if n == -1 {
+		n = len(input)
+	}
+	num, err := strconv.ParseFloat(input[:n], 64)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("expected a size, ie.. 512K, 10MB or 1GiB")
+	}
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(input[n:]))]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", input[n:])
+	}
+	size := num * float64(unit)
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("size out of range")
+	}
+	return int64(size), nil
+}
+
+func (S *sizeValue) String() string {
+	if S.value == nil {
+		return "0B"
+	}
+	return formatSize(*S.value)
+}
+
+func (S *sizeValue) Set(value string) (err error) {
+	size, err := parseSize(value)
+	if err != nil {
+		return err
+	}
+	*S.value = size
+	return nil
+}
+
+func (S *sizeValue) Get() interface{} { return *S.value }
+
+// Size variable in bytes, accepting units, ie.. --limit=10MB
+// K, M, G and T are binary (1K is 1024), as are KiB, MiB, GiB and TiB, while KB, MB, GB and TB are decimal (1KB is 1000).
+func (E *EFlagSet) Size(name string, value int64, usage string) *int64 {
+	output := new(int64)
+	E.SizeVar(output, name, value, usage)
+	return output
+}
+
+// Size variable in bytes, accepting units, ie.. --limit=10MB
+// K, M, G and T are binary (1K is 1024), as are KiB, MiB, GiB and TiB, while KB, MB, GB and TB are decimal (1KB is 1000).
+func (E *EFlagSet) SizeVar(p *int64, name string, value int64, usage string) {
+	*p = value
+	E.Var(&sizeValue{p}, name, usage)
+}
+
+var (
+	Size    = cmd.Size
+	SizeVar = cmd.SizeVar
+)
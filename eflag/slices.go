@@ -0,0 +1,143 @@
+package eflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Typed slice of comma-separated values, each element parsed and validated on Set.
+type sliceValue struct {
+	reset func()
+	add   func(item string) error
+	items func() []string
+	get   func() interface{}
+}
+
+func (S *sliceValue) String() string {
+	if S.items == nil {
+		return ""
+	}
+	return strings.Join(S.items(), ",")
+}
+
+func (S *sliceValue) Set(value string) error {
+	S.reset()
+	for i, item := range string_split(value) {
+		item = strings.TrimSpace(item)
+		if err := S.add(item); err != nil {
+			return fmt.Errorf("element %d (%q): %s", i+1, item, err)
+		}
+	}
+	return nil
+}
+
+func (S *sliceValue) Get() interface{} { return S.get() }
+
+// Adds (multi: comma-separated) to usage.
+func multiUsage(usage string) string {
+	if len(usage) > 0 {
+		usage = fmt.Sprintf("%s (multi: comma-separated)", usage)
+	}
+	return usage
+}
+
+// Slice of int variable, ie.. --ports=80,443,8080
+func (E *EFlagSet) IntSlice(name string, value []int, usage string) *[]int {
+	output := new([]int)
+	E.IntSliceVar(output, name, value, usage)
+	return output
+}
+
+// Slice of int variable, ie.. --ports=80,443,8080
+func (E *EFlagSet) IntSliceVar(p *[]int, name string, value []int, usage string) {
+	*p = append([]int(nil), value...)
+	E.Var(&sliceValue{
+		reset: func() { *p = nil },
+		add: func(item string) error {
+			v, err := strconv.ParseInt(item, 0, strconv.IntSize)
+			if err != nil {
+				return err.(*strconv.NumError).Err
+			}
+			*p = append(*p, int(v))
+			return nil
+		},
+		items: func() (out []string) {
+			for _, v := range *p {
+				out = append(out, strconv.Itoa(v))
+			}
+			return
+		},
+		get: func() interface{} { return *p },
+	}, name, multiUsage(usage))
+}
+
+// Slice of float64 variable, ie.. --ratios=0.5,1.25
+func (E *EFlagSet) Float64Slice(name string, value []float64, usage string) *[]float64 {
+	output := new([]float64)
+	E.Float64SliceVar(output, name, value, usage)
+	return output
+}
+
+// Slice of float64 variable, ie.. --ratios=0.5,1.25
+func (E *EFlagSet) Float64SliceVar(p *[]float64, name string, value []float64, usage string) {
+	*p = append([]float64(nil), value...)
+	E.Var(&sliceValue{
+		reset: func() { *p = nil },
+		add: func(item string) error {
+			v, err := strconv.ParseFloat(item, 64)
+			if err != nil {
+				return err.(*strconv.NumError).Err
+			}
+			*p = append(*p, v)
+			return nil
+		},
+		items: func() (out []string) {
+			for _, v := range *p {
+				out = append(out, strconv.FormatFloat(v, 'g', -1, 64))
+			}
+			return
+		},
+		get: func() interface{} { return *p },
+	}, name, multiUsage(usage))
+}
+
+// Slice of time.Duration variable, ie.. --retries=1s,5s,30s
+func (E *EFlagSet) DurationSlice(name string, value []time.Duration, usage string) *[]time.Duration {
+	output := new([]time.Duration)
+	E.DurationSliceVar(output, name, value, usage)
+	return output
+}
+
+// Slice of time.Duration variable, ie.. --retries=1s,5s,30s
+func (E *EFlagSet) DurationSliceVar(p *[]time.Duration, name string, value []time.Duration, usage string) {
+	*p = append([]time.Duration(nil), value...)
+	E.Var(&sliceValue{
+		reset: func() { *p = nil },
+		add: func(item string) error {
+			v, err := time.ParseDuration(item)
+			if err != nil {
+				return fmt.Errorf("invalid duration")
+			}
+			*p = append(*p, v)
+			return nil
+		},
+		items: func() (out []string) {
+			for _, v := range *p {
+				out = append(out, v.String())
+			}
+			return
+		},
+		get: func() interface{} { return *p },
+	}, name, multiUsage(usage))
+}
+
+var (
+	IntSlice         = cmd.IntSlice
+	IntSliceVar      = cmd.IntSliceVar
+	Float64Slice     = cmd.Float64Slice
+	Float64SliceVar  = cmd.Float64SliceVar
+	DurationSlice    = cmd.DurationSlice
+	DurationSliceVar = cmd.DurationSliceVar
+)
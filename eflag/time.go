@@ -0,0 +1,58 @@
+package eflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts accepted by Time when none are given.
+var DefaultTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+type timeValue struct {
+	value   *time.Time
+	layouts []string
+}
+
+func (T *timeValue) String() string {
+	if T.value == nil || T.value.IsZero() {
+		return ""
+	}
+	return T.value.Format(T.layouts[0])
+}
+
+// Parses value with the first matching layout, times without a zone are local.
+func (T *timeValue) Set(value string) error {
+	for _, layout := range T.layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			*T.value = t
+			return nil
+		}
+	}
+	return fmt.Errorf("expected time in format %s", strings.Join(T.layouts, " or "))
+}
+
+func (T *timeValue) Get() interface{} { return *T.value }
+
+func (T *timeValue) zero() { *T.value = time.Time{} }
+
+// Time variable, parsed with layouts, or DefaultTimeLayouts if none are given, ie.. --since=2024-01-31
+func (E *EFlagSet) Time(name string, value time.Time, usage string, layouts ...string) *time.Time {
+	output := new(time.Time)
+	E.TimeVar(output, name, value, usage, layouts...)
+	return output
+}
+
+// Time variable, parsed with layouts, or DefaultTimeLayouts if none are given, ie.. --since=2024-01-31
+func (E *EFlagSet) TimeVar(p *time.Time, name string, value time.Time, usage string, layouts ...string) {
+	*p = value
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	E.Var(&timeValue{p, layouts}, name, usage)
+}
+
+var (
+	Time    = cmd.Time
+	TimeVar = cmd.TimeVar
+)
@@ -0,0 +1,34 @@
+package eflag
+
+import "fmt"
+
+// Validation callback for a flag.
+type validator struct {
+	name string
+	fn   func(value string) error
+}
+
+// Registers fn to check the value of flag name during Parse, when the flag is set.
+// An error from fn is reported as "invalid value for --name: <error>", along with usage.
+func (s *EFlagSet) Validate(name string, fn func(value string) error) {
+	s.validators = append(s.validators, validator{name, fn})
+}
+
+// Runs validation callbacks against flags which are set.
+func (s *EFlagSet) checkValid() error {
+	for _, v := range s.validators {
+		if s.Source(v.name) == "default" {
+			continue
+		}
+		f := s.Lookup(v.name)
+		if f == nil {
+			continue
+		}
+		if err := v.fn(f.Value.String()); err != nil {
+			return fmt.Errorf("invalid value for %s: %s", flagName(v.name), err)
+		}
+	}
+	return nil
+}
+
+var Validate = cmd.Validate
@@ -5,9 +5,7 @@ go 1.20
 require (
 	github.com/boltdb/bolt v1.3.1
 	golang.org/x/crypto v0.22.0
+	golang.org/x/sys v0.19.0
 )
 
-require (
-	golang.org/x/sys v0.19.0 // indirect
-	golang.org/x/term v0.19.0 // indirect
-)
+require golang.org/x/term v0.19.0 // indirect
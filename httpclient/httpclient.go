@@ -0,0 +1,266 @@
+/*
+Package 'httpclient' wraps net/http with retries and backoff, idle timeouts on response bodies through iotimeout,
+request and response logging through nfo with secrets redacted, and multipart uploads through mimebody with progress from nfo's TransferMonitor.
+*/
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/cmcoffee/snugforge/iotimeout"
+	"github.com/cmcoffee/snugforge/mimebody"
+	"github.com/cmcoffee/snugforge/nfo"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Client is an http.Client with retry, timeout and logging.
+type Client struct {
+	*http.Client
+	Retries      int                                               // Number of retries after the first attempt.
+	Backoff      time.Duration                                     // Wait before the first retry, doubled for each retry after.
+	MaxBackoff   time.Duration                                     // Maximum wait between retries.
+	Timeout      time.Duration                                     // Idle timeout when reading a response body, 0 disables.
+	Logging      bool                                              // Log requests and responses with nfo.Debug, headers with nfo.Trace.
+	RedactHeader []string                                          // Headers whose values are redacted from logs.
+	RedactParams []string                                          // Query parameters whose values are redacted from logs.
+	RetryOn      func(resp *http.Response, err error) (retry bool) // Decides if an attempt should be retried, see DefaultRetryOn.
+}
+
+// Creates a new Client with default settings.
+func New() *Client {
+	return &Client{
+		Client:       new(http.Client),
+		Retries:      3,
+		Backoff:      time.Second,
+		MaxBackoff:   30 * time.Second,
+		RedactHeader: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"},
+		RedactParams: []string{"access_token", "refresh_token", "token", "password", "secret", "api_key", "client_secret"},
+		RetryOn:      DefaultRetryOn,
+	}
+}
+
+// Retries network errors, 429 Too Many Requests and 5xx responses, for idempotent methods only, as a POST or PATCH may have taken effect.
+func DefaultRetryOn(resp *http.Response, err error) bool {
+	if err != nil {
+		var u_err *url.Error
+		if errors.As(err, &u_err) && !idempotent(u_err.Op) {
+			return false
+		}
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp.Request != nil && !idempotent(resp.Request.Method) {
+		return false
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// Returns true if method can be repeated without further effect.
+func idempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Sends req, retrying with backoff when RetryOn allows.
+// Requests with a body are only retried when req.GetBody is set, as it is by http.NewRequest for in-memory bodies.
+func (C *Client) Do(req *http.Request) (resp *http.Response, err error) {
+	client := C.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	retry_on := C.RetryOn
+	if retry_on == nil {
+		retry_on = DefaultRetryOn
+	}
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		C.logRequest(req, attempt)
+		resp, err = client.Do(req)
+		C.logResponse(req, resp, err)
+
+		if attempt >= C.Retries || !replayable || !retry_on(resp, err) {
+			break
+		}
+
+		wait := C.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if err == nil && C.Timeout > 0 {
+		resp.Body = iotimeout.NewReadCloser(resp.Body, C.Timeout)
+	}
+	return resp, err
+}
+
+// Calculates wait before next attempt, honoring Retry-After up to MaxBackoff.
+func (C *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.ParseInt(resp.Header.Get("Retry-After"), 10, 64); err == nil && secs >= 0 {
+			if secs > math.MaxInt64/int64(time.Second) {
+				secs = math.MaxInt64 / int64(time.Second)
+			}
+			wait := time.Duration(secs) * time.Second
+			if C.MaxBackoff > 0 && wait > C.MaxBackoff {
+				return C.MaxBackoff
+			}
+			return wait
+		}
+	}
+	wait := C.Backoff
+	for i := 0; i < attempt && wait <= math.MaxInt64/2; i++ {
+		wait *= 2
+		if C.MaxBackoff > 0 && wait > C.MaxBackoff {
+			return C.MaxBackoff
+		}
+	}
+	return wait
+}
+
+// Sends a GET request to url.
+func (C *Client) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return C.Do(req)
+}
+
+// Uploads source as a multipart form file under fieldname, along with fields, showing progress with nfo.TransferMonitor.
+// size is the size of source, or -1 if unknown. Uploads are retried only if source implements io.Seeker and RetryOn allows it, DefaultRetryOn does not retry a POST.
+func (C *Client) Upload(req *http.Request, fieldname, filename string, source io.ReadCloser, size int64, fields map[string]string) (*http.Response, error) {
+	var monitor nfo.ReadSeekCloser
+	if seeker, ok := source.(nfo.ReadSeekCloser); ok {
+		monitor = nfo.TransferMonitor(filename, size, 0, seeker)
+	} else {
+		monitor = nfo.TransferMonitor(filename, size, 0, nfo.NopSeeker(source))
+	}
+	defer monitor.Close()
+
+	limit := size
+	if limit <= 0 {
+		limit = -1
+	}
+
+	build := func() io.ReadCloser {
+		r := req.Clone(req.Context())
+		r.Body = io.NopCloser(monitor)
+		mimebody.ConvertFormFile(r, fieldname, filename, fields, limit)
+		req.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+		return r.Body
+	}
+
+	req.Body = build()
+	req.ContentLength = -1
+	req.GetBody = nil
+
+	if _, ok := source.(io.Seeker); ok {
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := monitor.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return build(), nil
+		}
+	}
+
+	return C.Do(req)
+}
+
+// Returns url with sensitive query parameters redacted.
+func (C *Client) redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if len(u.RawQuery) == 0 {
+		return u.String()
+	}
+	c := *u
+	query := c.Query()
+	for k := range query {
+		for _, name := range C.RedactParams {
+			if strings.EqualFold(k, name) {
+				query.Set(k, redacted)
+			}
+		}
+	}
+	c.RawQuery = query.Encode()
+	return c.String()
+}
+
+// Returns headers as text, with sensitive values redacted.
+func (C *Client) redactHeader(header http.Header) string {
+	var lines []string
+	for k, v := range header {
+		value := strings.Join(v, ", ")
+		for _, name := range C.RedactHeader {
+			if strings.EqualFold(k, name) {
+				value = redacted
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", k, value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Logs outgoing request.
+func (C *Client) logRequest(req *http.Request, attempt int) {
+	if !C.Logging {
+		return
+	}
+	if attempt > 0 {
+		nfo.Debug("--> %s %s (retry %d of %d)", req.Method, C.redactURL(req.URL), attempt, C.Retries)
+	} else {
+		nfo.Debug("--> %s %s", req.Method, C.redactURL(req.URL))
+	}
+	if len(req.Header) > 0 {
+		nfo.Trace(C.redactHeader(req.Header))
+	}
+}
+
+// Logs response to request.
+func (C *Client) logResponse(req *http.Request, resp *http.Response, err error) {
+	if !C.Logging {
+		return
+	}
+	if err != nil {
+		// url.Error carries the unredacted URL.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		nfo.Debug("<-- %s %s: %s", req.Method, C.redactURL(req.URL), err)
+		return
+	}
+	nfo.Debug("<-- %s %s: %s", req.Method, C.redactURL(req.URL), resp.Status)
+	if len(resp.Header) > 0 {
+		nfo.Trace(C.redactHeader(resp.Header))
+	}
+}
@@ -9,57 +9,102 @@ import (
 	. "github.com/cmcoffee/snugforge/xsync"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var ErrTimeout = errors.New("Timeout reached while waiting for bytes.")
 
 const (
-	waiting = 1 << iota
-	halted
+	halted = 1 << iota
+	expired
 )
 
-// Timer for io tranfer
-func start_timer(timeout time.Duration, flag *BitFlag, input chan []byte, expired chan struct{}) {
-	timeout_seconds := int64(timeout.Round(time.Second).Seconds())
+// How often the watchdog checks pending reads.
+const resolution = time.Second / 4
 
-	var cnt int64
+// Shared watchdog for all pending reads, runs only while reads are in flight.
+var watchdog struct {
+	mutex   sync.Mutex
+	pending map[*readCloser]struct{}
+	running bool
+}
+
+// Adds reader to watchdog.
+func watch(t *readCloser) {
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+	if watchdog.pending == nil {
+		watchdog.pending = make(map[*readCloser]struct{})
+	}
+	watchdog.pending[t] = struct{}{}
+	if !watchdog.running {
+		watchdog.running = true
+		go patrol()
+	}
+}
+
+// Removes reader from watchdog.
+func unwatch(t *readCloser) {
+	watchdog.mutex.Lock()
+	defer watchdog.mutex.Unlock()
+	delete(watchdog.pending, t)
+}
 
+// Expires any pending reads that have exceeded their timeout.
+func patrol() {
+	var stale []*readCloser
 	for {
-		time.Sleep(time.Second)
-		if flag.Has(halted) {
-			input <- nil
-			break
+		time.Sleep(resolution)
+		now := time.Now().UnixNano()
+
+		watchdog.mutex.Lock()
+		if len(watchdog.pending) == 0 {
+			watchdog.running = false
+			watchdog.mutex.Unlock()
+			return
+		}
+		for t := range watchdog.pending {
+			timeout := atomic.LoadInt64(&t.timeout)
+			if timeout > 0 && now-atomic.LoadInt64(&t.started) >= timeout {
+				delete(watchdog.pending, t)
+				t.flag.Set(expired)
+				stale = append(stale, t)
+			}
 		}
+		watchdog.mutex.Unlock()
 
-		if flag.Has(waiting) {
-			cnt++
-			if timeout_seconds > 0 && cnt >= timeout_seconds {
-				flag.Set(halted)
-				expired <- struct{}{}
-				input <- nil
-				break
+		// Interrupt outside of the lock, as closing a source may block.
+		for _, t := range stale {
+			if t.interrupt != nil {
+				t.interrupt()
 			}
-		} else {
-			cnt = 0
-			flag.Set(waiting)
 		}
+		stale = stale[0:0]
 	}
 }
 
-type resp struct {
-	n   int
-	err error
+// Sources supporting deadlines, such as net.Conn.
+type deadliner interface {
+	SetReadDeadline(t time.Time) error
 }
 
 // Timeout Reader.
 type readCloser struct {
-	src     io.ReadCloser
-	flag    BitFlag
-	input   chan []byte
-	output  chan resp
-	expired chan struct{}
-	mutex   sync.Mutex
+	src       io.ReadCloser
+	interrupt func()
+	flag      BitFlag
+	timeout   int64
+	started   int64
+	mutex     sync.Mutex
+	wake      chan struct{}
+	buf       []byte
+}
+
+// Result of a read made on a helper goroutine.
+type asyncRead struct {
+	n   int
+	err error
 }
 
 type reader struct {
@@ -72,67 +117,126 @@ func (r reader) Close() (err error) {
 
 // Timeout Reader: Adds a time to io.Reader
 func NewReader(source io.Reader, timeout time.Duration) io.Reader {
-	return NewReadCloser(reader{source}, timeout)
+	if source == nil {
+		return source
+	}
+	t := newReadCloser(reader{source}, timeout)
+	if d, ok := source.(deadliner); ok {
+		t.interrupt = func() { d.SetReadDeadline(time.Now()) }
+	} else {
+		// Source can't be interrupted, reads are made on a helper goroutine that is abandoned on timeout.
+		t.wake = make(chan struct{}, 1)
+		t.interrupt = func() {
+			select {
+			case t.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return t
 }
 
 // Timeout ReadCloser: Adds a timer to io.ReadCloser
 func NewReadCloser(source io.ReadCloser, timeout time.Duration) io.ReadCloser {
-	t := new(readCloser)
 	if source == nil {
 		return source
 	}
-	t.src = source
-	t.input = make(chan []byte, 2)
-	t.output = make(chan resp, 1)
-	t.expired = make(chan struct{}, 1)
-
-	go start_timer(timeout, &t.flag, t.input, t.expired)
-
-	go func() {
-		var (
-			data resp
-			p    []byte
-		)
-		for {
-			p = <-t.input
-			if p == nil {
-				break
-			}
-			t.flag.Unset(waiting)
-			data.n, data.err = source.Read(p)
-			t.output <- data
-		}
-	}()
+	t := newReadCloser(source, timeout)
+	if d, ok := source.(deadliner); ok {
+		t.interrupt = func() { d.SetReadDeadline(time.Now()) }
+	} else {
+		t.interrupt = func() { source.Close() }
+	}
 	return t
 }
 
+func newReadCloser(source io.ReadCloser, timeout time.Duration) *readCloser {
+	return &readCloser{
+		src:     source,
+		timeout: int64(timeout),
+	}
+}
+
 // Time Sensitive Read function.
+// Blocked reads are interrupted by the shared watchdog, by way of read deadline, closing the source, or abandoning a helper goroutine.
+// Data read before the timeout is returned, the timeout is then reported on the next call.
 func (t *readCloser) Read(p []byte) (n int, err error) {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
-	if t.flag.Has(halted) {
+	if t.flag.Has(expired) {
+		return 0, ErrTimeout
+	}
+
+	if t.flag.Has(halted) || atomic.LoadInt64(&t.timeout) <= 0 {
 		return t.src.Read(p)
 	}
 
-	// Set an idle timer.
-	defer t.flag.Set(waiting)
+	atomic.StoreInt64(&t.started, time.Now().UnixNano())
+	watch(t)
+	if t.wake != nil {
+		n, err = t.asyncRead(p)
+	} else {
+		n, err = t.src.Read(p)
+	}
+	unwatch(t)
 
-	t.input <- p
+	if t.flag.Has(expired) {
+		if n > 0 {
+			return n, nil
+		}
+		return 0, ErrTimeout
+	}
+	return
+}
+
+// Reads from source on a helper goroutine, returning early when woken by the watchdog.
+func (t *readCloser) asyncRead(p []byte) (n int, err error) {
+	if cap(t.buf) < len(p) {
+		t.buf = make([]byte, len(p))
+	}
+	buf := t.buf[:len(p)]
+	result := make(chan asyncRead, 1)
+
+	go func() {
+		var r asyncRead
+		r.n, r.err = t.src.Read(buf)
+		result <- r
+	}()
 
 	select {
-	case data := <-t.output:
-		n = data.n
-		err = data.err
-	case <-t.expired:
-		t.flag.Set(halted)
-		return -1, ErrTimeout
+	case r := <-result:
+		return copy(p, buf[:r.n]), r.err
+	case <-t.wake:
+		// Source may have returned just as the timeout was reached.
+		select {
+		case r := <-result:
+			return copy(p, buf[:r.n]), r.err
+		default:
+		}
+		// The abandoned goroutine keeps the buffer, don't reuse it.
+		t.buf = nil
+		return 0, ErrTimeout
 	}
-	if err != nil {
-		t.flag.Set(halted)
+}
+
+// Changes the timeout, applies to any read currently in progress. A timeout of 0 disables the timer.
+func (t *readCloser) SetTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&t.timeout, int64(timeout))
+}
+
+// Adjuster is implemented by the readers returned from NewReader and NewReadCloser.
+type Adjuster interface {
+	SetTimeout(timeout time.Duration)
+}
+
+// Adjusts the timeout of a reader returned by NewReader or NewReadCloser, returns false if source is not a timeout reader.
+func SetTimeout(source io.Reader, timeout time.Duration) bool {
+	if t, ok := source.(Adjuster); ok {
+		t.SetTimeout(timeout)
+		return true
 	}
-	// Set an idle timer.
-	return
+	return false
 }
 
 // Close function for ReadCloser.
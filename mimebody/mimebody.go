@@ -46,8 +46,8 @@ func (s *streamReadCloser) Read(p []byte) (n int, err error) {
 	// Get length of incoming []byte slice.
 	p_len := int64(len(p))
 
-	if sz := s.chunkSize - s.size; sz > 0 || sz == -1 {
-		if sz > p_len || sz == -1 {
+	if sz := s.chunkSize - s.size; sz > 0 || s.chunkSize < 0 {
+		if sz > p_len || s.chunkSize < 0 {
 			sz = p_len
 		}
 
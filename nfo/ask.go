@@ -6,6 +6,7 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 	"strings"
 	"syscall"
+	"time"
 )
 
 var cancel = make(chan struct{})
@@ -19,12 +20,33 @@ func getEscape() func() {
 // Loop until a non-blank answer is given
 func NeedAnswer(prompt string, request func(prompt string) string) (output string) {
 	for output = request(prompt); output == ""; output = request(prompt) {
+		if NonInteractive() {
+			noAnswer(prompt, "an answer is required")
+		}
 	}
 	return output
 }
 
+// Asks until input passes validate, showing the error of each failed attempt.
+func GetValidatedInput(prompt string, validate func(input string) error) string {
+	for {
+		input := GetInput(prompt)
+		if err := validate(input); err != nil {
+			if NonInteractive() {
+				noAnswer(prompt, err)
+			}
+			fmt.Printf("Invalid entry: %s\n", err)
+			continue
+		}
+		return input
+	}
+}
+
 // Prompt to press enter.
 func PressEnter(prompt string) {
+	if NonInteractive() {
+		return
+	}
 	unesc := Defer(getEscape())
 	defer unesc()
 
@@ -38,8 +60,21 @@ func PressEnter(prompt string) {
 	fmt.Printf("\r%s\r", string(blank_line))
 }
 
+// Gets user input with def filled in, to be edited or accepted as is.
+func GetInputDefault(prompt string, def string) string {
+	return readLine(prompt, def, 0)
+}
+
+// Gets user input with def filled in, def is returned when no key is pressed within timeout, ie.. for unattended runs.
+func GetInputTimeout(prompt string, def string, timeout time.Duration) string {
+	return readLine(prompt, def, timeout)
+}
+
 // Get Hidden/Password input, without returning information to the screen.
 func GetSecret(prompt string) string {
+	if answer, ok := autoAnswer(prompt, prompt, "", true); ok {
+		return answer
+	}
 	unesc := Defer(getEscape())
 	defer unesc()
 
@@ -53,7 +88,11 @@ func GetSecret(prompt string) string {
 // Get confirmation
 func GetConfirm(prompt string) bool {
 	for {
-		resp := GetInput(fmt.Sprintf("%s (y/n): ", prompt))
+		question := fmt.Sprintf("%s (y/n): ", prompt)
+		if resp, ok := autoAnswer(prompt, question, "", false); ok {
+			return autoConfirm(prompt, resp, nil)
+		}
+		resp := GetInput(question)
 		resp = strings.ToLower(resp)
 		if resp == "y" || resp == "yes" {
 			return true
@@ -66,14 +105,22 @@ func GetConfirm(prompt string) bool {
 
 // Get confirmation w/ Default answer.
 func ConfirmDefault(prompt string, default_answer bool) bool {
+	return ConfirmTimeout(prompt, default_answer, 0)
+}
+
+// Get confirmation w/ Default answer, the default answer is used when no key is pressed within timeout.
+func ConfirmTimeout(prompt string, default_answer bool, timeout time.Duration) bool {
 	for {
 		var question string
 		if default_answer {
 			question = fmt.Sprintf("%s (Y/n): ", prompt)
 		} else {
-			question = fmt.Sprintf("%s (y/N): ")
+			question = fmt.Sprintf("%s (y/N): ", prompt)
 		}
-		resp := GetInput(question)
+		if resp, ok := autoAnswer(prompt, question, "", false); ok {
+			return autoConfirm(prompt, resp, &default_answer)
+		}
+		resp := readLine(question, "", timeout)
 		resp = strings.ToLower(resp)
 		resp = strings.TrimSpace(resp)
 		switch resp {
@@ -88,6 +135,24 @@ func ConfirmDefault(prompt string, default_answer bool) bool {
 	}
 }
 
+// Returns the non-interactive answer to a confirmation, blank answers take def.
+func autoConfirm(prompt string, resp string, def *bool) bool {
+	switch strings.ToLower(strings.TrimSpace(resp)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	case "":
+		if def != nil {
+			return *def
+		}
+		noAnswer(prompt, "an answer of yes or no is required")
+	default:
+		noAnswer(prompt, fmt.Sprintf("%q is not yes or no", resp))
+	}
+	return false
+}
+
 // Removes newline characters
 func cleanInput(input string) (output string) {
 	var output_bytes []rune
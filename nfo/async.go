@@ -0,0 +1,114 @@
+package nfo
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Entry waiting for the background writer.
+type asyncEntry struct {
+	flag    uint32
+	ctx     context.Context
+	fields  []field
+	msg     string
+	ts      time.Time
+	flushed chan struct{}
+}
+
+var async struct {
+	mutex   sync.RWMutex
+	queue   chan asyncEntry
+	done    chan struct{}
+	pending int64 // Entries queued and not yet written.
+}
+
+// Enables asynchronous logging, entries are queued for a background writer rather than written under lock by the caller.
+// Logging blocks once size entries are waiting, pending entries are written on FlushAsync, CloseAsync, or shutdown.
+func EnableAsync(size int) {
+	async.mutex.Lock()
+	defer async.mutex.Unlock()
+	if async.queue != nil {
+		return
+	}
+	async.queue = make(chan asyncEntry, size)
+	async.done = make(chan struct{})
+	go drainAsync(async.queue, async.done)
+	Defer(CloseAsync)
+}
+
+// Blocks until all entries queued by asynchronous logging are written.
+func FlushAsync() {
+	async.mutex.RLock()
+	queue := async.queue
+	if queue == nil {
+		async.mutex.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	queue <- asyncEntry{flushed: flushed}
+	async.mutex.RUnlock()
+	<-flushed
+}
+
+// Writes pending entries and returns to synchronous logging.
+func CloseAsync() {
+	async.mutex.Lock()
+	defer async.mutex.Unlock()
+	if async.queue == nil {
+		return
+	}
+	close(async.queue)
+	<-async.done
+	async.queue = nil
+}
+
+// Queues an entry if asynchronous, returns false if the entry should be written now.
+// Entries bypassing the lock, ie.. Fatal, and screen only text, ie.. Stdout, flush the queue first to keep their order.
+// Flash repaints are frequent, so rather than waiting on the queue they are queued behind any pending entries.
+func queueEntry(flag uint32, ctx context.Context, fields []field, bypass bool, vars ...interface{}) bool {
+	if bypass {
+		FlushAsync()
+		return false
+	}
+	if flag&_no_logging != 0 {
+		if atomic.LoadInt64(&async.pending) == 0 {
+			return false
+		}
+		if flag&_flash_txt == 0 {
+			FlushAsync()
+			return false
+		}
+	}
+
+	async.mutex.RLock()
+	defer async.mutex.RUnlock()
+	if async.queue == nil {
+		return false
+	}
+
+	var buf bytes.Buffer
+	fprintf(&buf, vars...)
+	atomic.AddInt64(&async.pending, 1)
+	async.queue <- asyncEntry{flag, ctx, fields, buf.String(), time.Now(), nil}
+	return true
+}
+
+// Writes queued entries until the queue is closed.
+func drainAsync(queue chan asyncEntry, done chan struct{}) {
+	for e := range queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		mutex.Lock()
+		msgBuffer.Reset()
+		msgBuffer.WriteString(e.msg)
+		writeEntry(e.flag, e.ctx, e.fields, e.ts)
+		mutex.Unlock()
+		atomic.AddInt64(&async.pending, -1)
+	}
+	close(done)
+}
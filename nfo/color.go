@@ -0,0 +1,58 @@
+package nfo
+
+import "os"
+
+// Colorize loggers on terminal output, disabled when output is piped, see SetColorScheme.
+var Colors = false
+
+// Default colors, as ANSI SGR parameters.
+var color_scheme = map[uint32]string{
+	ERROR:  "31",   // Red
+	FATAL:  "1;31", // Bold Red
+	WARN:   "33",   // Yellow
+	NOTICE: "36",   // Cyan
+	DEBUG:  "2",    // Dim
+	TRACE:  "2",    // Dim
+}
+
+// Sets colors of loggers when Colors is enabled, as ANSI SGR parameters, ie.. map[uint32]string{ERROR: "31", WARN|NOTICE: "1;33"}
+// Loggers not in scheme are not colored.
+func SetColorScheme(scheme map[uint32]string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	color_scheme = make(map[uint32]string)
+	for k, v := range scheme {
+		for l := range l_map {
+			if k&l == l {
+				color_scheme[l] = v
+			}
+		}
+	}
+}
+
+// Returns true if output is a terminal.
+func isTerminal(output interface{}) bool {
+	return (output == os.Stdout && !piped_stdout) || (output == os.Stderr && !piped_stderr)
+}
+
+// Wraps line in the color of logger flag, when colors are enabled and output is a terminal.
+func colorize(flag uint32, output interface{}, line []byte) []byte {
+	if !Colors || no_ansi || !isTerminal(output) {
+		return line
+	}
+	color, ok := color_scheme[flag]
+	if !ok || len(line) == 0 {
+		return line
+	}
+	var newline bool
+	if line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+		newline = true
+	}
+	out := append([]byte("\x1b["+color+"m"), line...)
+	out = append(out, "\x1b[0m"...)
+	if newline {
+		out = append(out, '\n')
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+//go:build windows
+// +build windows
+
+package nfo
+
+import (
+	"os"
+	"syscall"
+)
+
+const enable_virtual_terminal_processing = 0x4
+
+// Enables escape sequences on the console for colors and multi-line Flash text, consoles without support fall back to plain output.
+func init() {
+	set_mode := syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+	if set_mode.Find() != nil {
+		no_ansi = true
+		return
+	}
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		var mode uint32
+		// Not a console, ie.. output is piped.
+		if syscall.GetConsoleMode(syscall.Handle(f.Fd()), &mode) != nil {
+			continue
+		}
+		if mode&enable_virtual_terminal_processing != 0 {
+			continue
+		}
+		if r, _, _ := set_mode.Call(f.Fd(), uintptr(mode|enable_virtual_terminal_processing)); r == 0 {
+			no_ansi = true
+		}
+	}
+}
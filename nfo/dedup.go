@@ -0,0 +1,73 @@
+package nfo
+
+import (
+	"fmt"
+	"time"
+)
+
+var dup struct {
+	window time.Duration
+	flag   uint32
+	msg    string
+	count  int
+	since  time.Time
+	timer  *time.Timer
+}
+
+// Collapses consecutive identical messages logged within window into "last message repeated N times", 0 disables.
+func SuppressDuplicates(window time.Duration) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if window <= 0 && dup.count > 0 {
+		emitRepeated(time.Now())
+	}
+	dup.window = window
+	dup.msg = ""
+}
+
+// Returns true if the message in msgBuffer repeats the last one, mutex must be held.
+func suppressDuplicate(flag uint32, fields []field, now time.Time) bool {
+	if dup.window <= 0 {
+		return false
+	}
+
+	msg := msgBuffer.String() + string(appendFields(nil, fields))
+	if flag == dup.flag && msg == dup.msg && now.Sub(dup.since) < dup.window {
+		dup.count++
+		if dup.timer == nil {
+			dup.timer = time.AfterFunc(dup.window-now.Sub(dup.since), func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				dup.timer = nil
+				if dup.count > 0 {
+					emitRepeated(time.Now())
+					dup.msg = ""
+				}
+			})
+		}
+		return true
+	}
+
+	if dup.count > 0 {
+		text := msgBuffer.String()
+		emitRepeated(now)
+		msgBuffer.Reset()
+		msgBuffer.WriteString(text)
+	}
+	dup.flag = flag
+	dup.msg = msg
+	dup.since = now
+	return false
+}
+
+// Writes the repeat count of the last message, mutex must be held.
+func emitRepeated(now time.Time) {
+	if dup.timer != nil {
+		dup.timer.Stop()
+		dup.timer = nil
+	}
+	msgBuffer.Reset()
+	fmt.Fprintf(&msgBuffer, "last message repeated %d times", dup.count)
+	dup.count = 0
+	writeOutput(dup.flag, nil, nil, now)
+}
@@ -12,11 +12,12 @@ import (
 
 var (
 	// Signal Notification Channel. (ie..nfo.Signal<-os.Kill will initiate a shutdown.)
-	signalChan  = make(chan os.Signal)
+	signalChan  = make(chan os.Signal, 1)
 	globalDefer struct {
 		mutex sync.RWMutex
 		ids   []string
 		d_map map[string]func() error
+		names map[string]string
 	}
 	errCode   = 0
 	wait      sync.WaitGroup
@@ -32,12 +33,15 @@ func ShutdownInProgress() bool {
 }
 
 // Global wait group, allows running processes to finish up tasks before app shutdown
+// Shutdown on Fatal does not wait on holds, a holder calling Exit itself waits for SetShutdownTimeout.
 func BlockShutdown() {
 	wait.Add(1)
+	trackBlocker(callerName(1), 1)
 }
 
 // Task completed, carry on with shutdown.
 func UnblockShutdown() {
+	trackBlocker(callerName(1), -1)
 	wait.Done()
 }
 
@@ -89,11 +93,13 @@ func Defer(closer interface{}) func() error {
 
 	globalDefer.ids = append(globalDefer.ids, id)
 	globalDefer.d_map[id] = d
+	globalDefer.names[id] = funcName(closer)
 
 	return func() error {
 		globalDefer.mutex.Lock()
 		defer globalDefer.mutex.Unlock()
 		delete(globalDefer.d_map, id)
+		delete(globalDefer.names, id)
 		for i := len(globalDefer.ids) - 1; i > -1; i-- {
 			if globalDefer.ids[i] == id {
 				globalDefer.ids = append(globalDefer.ids[:i], globalDefer.ids[i+1:]...)
@@ -111,6 +117,7 @@ func Exit(exit_code int) {
 		Fatal("(panic) %s", string(debug.Stack()))
 	} else {
 		atomic.StoreInt32(&fatal_triggered, 2) // Ignore any Fatal() calls, we've been told to exit.
+		errCode = exit_code
 		signalChan <- os.Kill
 		<-exit_lock
 		os.Exit(exit_code)
@@ -136,6 +143,7 @@ var callbacks = make(map[os.Signal]func() bool)
 
 func init() {
 	globalDefer.d_map = make(map[string]func() error)
+	globalDefer.names = make(map[string]string)
 	SetSignals(syscall.SIGINT, syscall.SIGKILL, syscall.SIGTERM, syscall.SIGHUP)
 	go func() {
 		for {
@@ -165,20 +173,27 @@ func init() {
 			break
 		}
 
+		startShutdownTimer()
+
 		globalDefer.mutex.RLock()
 		defer globalDefer.mutex.RUnlock()
 
 		// Run through all globalDefer functions.
 		for i := len(globalDefer.ids) - 1; i >= 0; i-- {
+			d := globalDefer.d_map[globalDefer.ids[i]]
+			setRunning(globalDefer.names[globalDefer.ids[i]])
 			globalDefer.mutex.RUnlock()
-			if err := globalDefer.d_map[globalDefer.ids[i]](); err != nil {
+			if err := d(); err != nil {
 				write2log(ERROR|_bypass_lock, err.Error())
 			}
 			globalDefer.mutex.RLock()
 		}
+		setRunning("")
 
-		// Wait on any process that have access to wait.
-		wait.Wait()
+		// Wait on any process that have access to wait, unless shutting down on Fatal, which may have been called by a holder.
+		if atomic.LoadInt32(&fatal_triggered) != 1 {
+			wait.Wait()
+		}
 
 		// Hide Please Wait
 		PleaseWait.Hide()
@@ -0,0 +1,31 @@
+//go:build windows
+// +build windows
+
+package nfo
+
+import "syscall"
+
+const (
+	ctrl_close_event    = 2
+	ctrl_shutdown_event = 6
+)
+
+// Runs the global defer when the console window is closed or the system shuts down.
+func init() {
+	set_handler := syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleCtrlHandler")
+	if set_handler.Find() != nil {
+		return
+	}
+	set_handler.Call(syscall.NewCallback(consoleCtrlHandler), 1)
+}
+
+// Console control handler, other events such as CTRL_C are left to the Go runtime, which delivers them as signals.
+func consoleCtrlHandler(ctrl_type uintptr) uintptr {
+	switch ctrl_type {
+	case ctrl_close_event, ctrl_shutdown_event:
+		signalChan <- syscall.SIGTERM
+		// Windows ends the process once the handler returns, so wait on shutdown to exit.
+		select {}
+	}
+	return 0
+}
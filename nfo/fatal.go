@@ -0,0 +1,26 @@
+package nfo
+
+var fatal_handler func(msg string, code int)
+
+// Sets a function called by Fatal after the fatal message is logged, and before the global defer and exit.
+// Useful for error reporting or cleanup which must happen first, the process still exits with code once the handler returns.
+func SetFatalHandler(fn func(msg string, code int)) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	fatal_handler = fn
+}
+
+// Log as Fatal, then quit with exit code.
+func FatalCode(code int, vars ...interface{}) {
+	fatal(code, nil, nil, vars...)
+}
+
+// Calls the fatal handler, if set.
+func runFatalHandler(code int, vars ...interface{}) {
+	mutex.Lock()
+	fn := fatal_handler
+	mutex.Unlock()
+	if fn != nil {
+		fn(Stringer(vars...), code)
+	}
+}
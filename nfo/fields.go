@@ -0,0 +1,122 @@
+package nfo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Key/value pair carried by a Logger.
+type field struct {
+	key   string
+	value interface{}
+}
+
+// Logger which adds key/value fields to every entry, ie.. nfo.With("request_id", id).Log("done")
+type Logger struct {
+	ctx    context.Context
+	fields []field
+}
+
+// Returns a Logger whose entries carry key and value.
+func With(key string, value interface{}) *Logger {
+	return new(Logger).With(key, value)
+}
+
+// Returns a new Logger with the fields of L, plus key and value.
+func (L *Logger) With(key string, value interface{}) *Logger {
+	fields := make([]field, len(L.fields), len(L.fields)+1)
+	copy(fields, L.fields)
+	return &Logger{L.ctx, append(fields, field{key, value})}
+}
+
+// Returns a Logger whose entries carry ctx, for exporters such as HookOTel to correlate with traces.
+func WithContext(ctx context.Context) *Logger {
+	return new(Logger).WithContext(ctx)
+}
+
+// Returns a new Logger with the fields of L, carrying ctx.
+func (L *Logger) WithContext(ctx context.Context) *Logger {
+	return &Logger{ctx, L.fields}
+}
+
+// Log as Info.
+func (L *Logger) Log(vars ...interface{}) {
+	writeLog(INFO, L.ctx, L.fields, vars...)
+}
+
+// Log as Error.
+func (L *Logger) Err(vars ...interface{}) {
+	writeLog(ERROR, L.ctx, L.fields, vars...)
+}
+
+// Log as Warn.
+func (L *Logger) Warn(vars ...interface{}) {
+	writeLog(WARN, L.ctx, L.fields, vars...)
+}
+
+// Log as Notice.
+func (L *Logger) Notice(vars ...interface{}) {
+	writeLog(NOTICE, L.ctx, L.fields, vars...)
+}
+
+// Log as Info, as auxiliary output.
+func (L *Logger) Aux(vars ...interface{}) {
+	writeLog(AUX, L.ctx, L.fields, vars...)
+}
+
+// Log as Info, as auxiliary output.
+func (L *Logger) Aux2(vars ...interface{}) {
+	writeLog(AUX2, L.ctx, L.fields, vars...)
+}
+
+// Log as Info, as auxiliary output.
+func (L *Logger) Aux3(vars ...interface{}) {
+	writeLog(AUX3, L.ctx, L.fields, vars...)
+}
+
+// Log as Info, as auxiliary output.
+func (L *Logger) Aux4(vars ...interface{}) {
+	writeLog(AUX4, L.ctx, L.fields, vars...)
+}
+
+// Log as Debug.
+func (L *Logger) Debug(vars ...interface{}) {
+	writeLog(DEBUG, L.ctx, L.fields, vars...)
+}
+
+// Log as Trace.
+func (L *Logger) Trace(vars ...interface{}) {
+	writeLog(TRACE, L.ctx, L.fields, vars...)
+}
+
+// Log as Fatal, then quit.
+func (L *Logger) Fatal(vars ...interface{}) {
+	fatal(1, L.ctx, L.fields, vars...)
+}
+
+// Renders the value of a field as text.
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Time:
+		return v.In(timezone).Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// Appends fields as ' key=value' pairs.
+func appendFields(output []byte, fields []field) []byte {
+	for _, f := range fields {
+		output = append(output, ' ')
+		output = append(output, f.key...)
+		output = append(output, '=')
+		output = appendLogfmtValue(output, fieldString(f.value))
+	}
+	return output
+}
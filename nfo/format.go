@@ -0,0 +1,122 @@
+package nfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Output format of a logger.
+type Format int
+
+const (
+	TEXT   Format = iota // Plain text, ie.. [ERROR] message (Default Setting)
+	LOGFMT               // logfmt key/value pairs, ie.. level=error ts=2006-01-02T15:04:05Z msg="message"
+	JSON                 // JSON object per line, ie.. {"level":"error","ts":"2006-01-02T15:04:05Z","msg":"message"}
+)
+
+// Sets the format of screen output for specified loggers.
+func SetFormat(flag uint32, format Format) {
+	updateLogger(flag, textFormat, format)
+}
+
+// Sets the format of file output for specified loggers.
+func SetFileFormat(flag uint32, format Format) {
+	updateLogger(flag, fileFormat, format)
+}
+
+// Returns the level name of a logger, ie.. error
+func levelName(flag uint32) string {
+	switch flag {
+	case INFO:
+		return "info"
+	case ERROR:
+		return "error"
+	case WARN:
+		return "warn"
+	case NOTICE:
+		return "notice"
+	case DEBUG:
+		return "debug"
+	case TRACE:
+		return "trace"
+	case FATAL:
+		return "fatal"
+	case AUX:
+		return "aux"
+	case AUX2:
+		return "aux2"
+	case AUX3:
+		return "aux3"
+	case AUX4:
+		return "aux4"
+	}
+	return "info"
+}
+
+// Renders a logfmt line.
+func genLogfmt(flag uint32, ts time.Time, msg string, fields []field) (output []byte) {
+	output = append(output, "level="...)
+	output = append(output, levelName(flag)...)
+	output = append(output, " ts="...)
+	output = ts.In(timezone).AppendFormat(output, time.RFC3339)
+	output = append(output, " msg="...)
+	output = appendLogfmtValue(output, strings.TrimRight(msg, "\r\n"))
+	output = appendFields(output, fields)
+	return append(output, '\n')
+}
+
+// Renders a JSON line, fields are added as members of the object.
+func genJSON(flag uint32, ts time.Time, msg string, fields []field) (output []byte) {
+	output = append(output, `{"level":`...)
+	output = strconv.AppendQuote(output, levelName(flag))
+	output = append(output, `,"ts":`...)
+	output = strconv.AppendQuote(output, ts.In(timezone).Format(time.RFC3339))
+	output = append(output, `,"msg":`...)
+	output = appendJSON(output, strings.TrimRight(msg, "\r\n"))
+	for _, f := range fields {
+		output = append(output, ',')
+		output = appendJSON(output, f.key)
+		output = append(output, ':')
+		switch v := f.value.(type) {
+		case error, time.Time, fmt.Stringer:
+			output = appendJSON(output, fieldString(v))
+		default:
+			output = appendJSON(output, v)
+		}
+	}
+	return append(output, "}\n"...)
+}
+
+// Appends value encoded as JSON, values which cannot be encoded are added as strings.
+func appendJSON(output []byte, value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	return append(output, data...)
+}
+
+// Renders an entry in format, TEXT output is left to the caller.
+func genFormat(format Format, flag uint32, ts time.Time, msg string, fields []field) []byte {
+	if format == JSON {
+		return genJSON(flag, ts, msg, fields)
+	}
+	return genLogfmt(flag, ts, msg, fields)
+}
+
+// Appends a logfmt value, quoted if it contains spaces, quotes, '=' or control characters.
+func appendLogfmtValue(output []byte, value string) []byte {
+	if value == "" {
+		return append(output, `""`...)
+	}
+	for _, r := range value {
+		if r == '"' || r == '=' || r == '\\' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.AppendQuote(output, value)
+		}
+	}
+	return append(output, value...)
+}
@@ -4,15 +4,97 @@
 package nfo
 
 import (
+	"errors"
 	"fmt"
 	"golang.org/x/crypto/ssh/terminal"
+	"golang.org/x/sys/unix"
 	"io"
 	"os"
 	"syscall"
+	"time"
 )
 
+// Terminal input and output of GetInput, pending is read ahead of stdin, ie.. a default to edit.
+type termInput struct {
+	pending  []byte
+	mute     bool
+	deadline time.Time // Time to wait for the first key until, when set.
+}
+
+var errTimeout = errors.New("timed out waiting for input")
+
+var (
+	input_io   = new(termInput)
+	input_term *terminal.Terminal // Terminal kept between calls for history, see EnableHistory.
+)
+
+func (T *termInput) Read(p []byte) (n int, err error) {
+	if len(T.pending) > 0 {
+		n = copy(p, T.pending)
+		T.pending = T.pending[n:]
+		return n, nil
+	}
+	if !T.deadline.IsZero() {
+		if err = waitInput(T.deadline); err != nil {
+			return 0, err
+		}
+		T.deadline = time.Time{}
+	}
+	return os.Stdin.Read(p)
+}
+
+// Waits until stdin has input, or deadline passes.
+func waitInput(deadline time.Time) error {
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return errTimeout
+		}
+		fds := []unix.PollFd{{Fd: int32(syscall.Stdin), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(wait/time.Millisecond)+1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+}
+
+func (T *termInput) Write(p []byte) (n int, err error) {
+	if T.mute {
+		return len(p), nil
+	}
+	return os.Stdin.Write(p)
+}
+
+// Keeps the terminal between calls, with lines entered as history.
+func startHistory(lines []string) {
+	input_io.mute = true
+	defer func() { input_io.mute = false }()
+
+	input_term = terminal.NewTerminal(input_io, "")
+	for _, line := range lines {
+		input_io.pending = []byte(line + "\r")
+		input_term.ReadLine()
+	}
+}
+
 // Gets user input, used during setup and configuration.
 func GetInput(prompt string) string {
+	return readLine(prompt, "", 0)
+}
+
+// Reads a line from the terminal, with def filled in to be edited.
+// When timeout is set, def is returned if no key is pressed within it.
+func readLine(prompt string, def string, timeout time.Duration) string {
+	if answer, ok := autoAnswer(prompt, prompt, def, false); ok {
+		return answer
+	}
+
 	unesc := Defer(getEscape())
 	defer unesc()
 
@@ -26,13 +108,30 @@ func GetInput(prompt string) string {
 	)
 
 	for {
-		t := terminal.NewTerminal(os.Stdin, "")
+		// A line left unfinished by a timeout can't be cleared from the terminal, so timed reads don't use history.
+		t := input_term
+		if t == nil || timeout > 0 {
+			t = terminal.NewTerminal(input_io, "")
+		}
+		input_io.pending = []byte(def)
+		if timeout > 0 {
+			input_io.deadline = time.Now().Add(timeout)
+		}
 		str, err = t.ReadLine()
+		input_io.deadline = time.Time{}
+		if err == errTimeout {
+			fmt.Printf("\r\n")
+			return cleanInput(def)
+		}
 		if err == io.EOF {
 			signalChan <- syscall.SIGINT
 			continue
 		}
 		break
 	}
-	return cleanInput(str)
+	output := cleanInput(str)
+	if input_term != nil && timeout == 0 {
+		saveHistory(output)
+	}
+	return output
 }
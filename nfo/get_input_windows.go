@@ -4,14 +4,56 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"syscall"
+	"time"
 )
 
 // Gets user input, used during setup and configuration.
 func GetInput(prompt string) string {
+	if answer, ok := autoAnswer(prompt, prompt, "", false); ok {
+		return answer
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Printf(prompt)
 	response, _ := reader.ReadString('\n')
 
-	return cleanInput(response)
+	output := cleanInput(response)
+	if history.enabled {
+		saveHistory(output)
+	}
+	return output
+}
+
+// The console keeps its own history.
+func startHistory(lines []string) {}
+
+// Reads a line from the console, the line can't be filled in, so def is shown and used when left blank.
+// When timeout is set, def is returned if no key is pressed within it.
+func readLine(prompt string, def string, timeout time.Duration) string {
+	if answer, ok := autoAnswer(prompt, prompt, def, false); ok {
+		return answer
+	}
+	if def != "" {
+		prompt = fmt.Sprintf("%s[%s] ", prompt, def)
+	}
+	if timeout > 0 {
+		fmt.Printf(prompt)
+		if !waitInput(timeout) {
+			fmt.Printf("\n")
+			return def
+		}
+		prompt = ""
+	}
+	if response := GetInput(prompt); response != "" {
+		return response
+	}
+	return def
+}
+
+// Waits until the console has input, returns false if timeout passes first.
+func waitInput(timeout time.Duration) bool {
+	event, err := syscall.WaitForSingleObject(syscall.Handle(os.Stdin.Fd()), uint32(timeout/time.Millisecond))
+	return err != nil || event != syscall.WAIT_TIMEOUT
 }
@@ -0,0 +1,63 @@
+package nfo
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+const history_max = 100 // Lines kept in the history file.
+
+var history struct {
+	file    string
+	enabled bool
+	mutex   sync.Mutex
+}
+
+// Keeps a history of GetInput answers for the session, recalled with the up and down arrows, and saved to file when file is not blank.
+// Lines are edited with left and right, ctrl-w removes the word before the cursor and ctrl-u the line before the cursor.
+// Windows consoles provide their own history and editing, answers are still saved to file there.
+func EnableHistory(file string) error {
+	var lines []string
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimRight(line, "\r"); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > history_max {
+			lines = lines[len(lines)-history_max:]
+			if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+				return err
+			}
+		}
+	}
+
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+	history.file = file
+	history.enabled = true
+	startHistory(lines)
+	return nil
+}
+
+// Adds line to the history file.
+func saveHistory(line string) {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	if history.file == "" || line == "" {
+		return
+	}
+	f, err := os.OpenFile(history.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	f.WriteString(line + "\n")
+	f.Close()
+}
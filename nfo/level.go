@@ -0,0 +1,33 @@
+package nfo
+
+import "os"
+
+var (
+	screen_level = uint32(ALL)
+	file_level   = uint32(ALL)
+)
+
+// Sets which loggers are written to screen and file, ie.. SetLevel(STD|DEBUG)
+func SetLevel(flag uint32) {
+	SetScreenLevel(flag)
+	SetFileLevel(flag)
+}
+
+// Sets which loggers are written to screen, loggers enabled which have no output are sent to os.Stdout.
+func SetScreenLevel(flag uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	screen_level = flag
+	for k, v := range l_map {
+		if k&ALL != 0 && flag&k == k && v.textout == None {
+			v.textout = os.Stdout
+		}
+	}
+}
+
+// Sets which loggers are written to file, loggers still require a file from SetFile.
+func SetFileLevel(flag uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	file_level = flag
+}
@@ -34,6 +34,7 @@ type loading_backup struct {
 const (
 	loading_show = 1 << iota
 	transfer_monitor_active
+	spinner_active
 )
 
 func (B *loading_backup) Restore() {
@@ -74,7 +75,7 @@ func (L *loading) Set(message func() string, loader ...[]string) {
 	go func(message func() string, anim_1 []string, anim_2 []string, count int32) {
 		for count == atomic.LoadInt32(&L.counter) {
 			for i, str := range anim_1 {
-				if L.flag.Has(loading_show) && !L.flag.Has(transfer_monitor_active) && count == atomic.LoadInt32(&L.counter) {
+				if L.flag.Has(loading_show) && !L.flag.Has(transfer_monitor_active) && !L.flag.Has(spinner_active) && count == atomic.LoadInt32(&L.counter) {
 					Flash("%s %s %s", str, message(), anim_2[i])
 				}
 				time.Sleep(125 * time.Millisecond)
@@ -102,7 +103,7 @@ type ProgressBar interface {
 }
 
 type progressBar struct {
-	tm ReadSeekCloser
+	tm *tmon
 }
 
 type b_closer struct {
@@ -114,23 +115,25 @@ func (b b_closer) Close() error {
 }
 
 // Updates loading to be a progress bar.
-func NewProgressBar(name string, max int) ProgressBar {
+// Counts are shown with units when provided, ie.. NewProgressBar("Importing", 5000, Items("rows"))
+func NewProgressBar(name string, max int, units ...Units) ProgressBar {
 	x := new(progressBar)
-	var dummy b_closer
-	dummy.Reader = bytes.NewReader(make([]byte, max))
-
-	x.tm = TransferMonitor(name, int64(max), internal, dummy)
+	x.tm = newTmon(name, int64(max), internal, b_closer{bytes.NewReader(nil)})
+	if len(units) > 0 {
+		x.tm.units = units[0]
+	}
+	displayTransfer(x.tm)
 	return x
 }
 
 // Adds to progress bar.
 func (p *progressBar) Add(num int) {
-	p.tm.Read(make([]byte, num))
+	p.tm.count(num)
 }
 
 // Specify number set on progress bar.
 func (p *progressBar) Set(num int) {
-	p.tm.Seek(int64(num), 0)
+	atomic.StoreInt64(&p.tm.transferred, int64(num))
 }
 
 // Complete progress bar, return to loading.
@@ -5,6 +5,7 @@ package nfo
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/cmcoffee/snugforge/wrotate"
 	"golang.org/x/crypto/ssh/terminal"
@@ -15,7 +16,6 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
-	"unicode/utf8"
 )
 
 const (
@@ -48,6 +48,8 @@ const (
 	fileWriter
 	setTimestamp
 	setPrefix
+	textFormat
+	fileFormat
 )
 
 var (
@@ -57,30 +59,33 @@ var (
 	flush_line         []rune
 	flush_line_len     int
 	last_flash_len     int
+	last_flash_widths  []int
+	last_flash_cols    int
 	last_line          int
 	flush_needed       bool
 	piped_stdout       bool
 	piped_stderr       bool
+	no_ansi            bool
 	fatal_triggered    int32
 	msgBuffer          bytes.Buffer
 	enabled_exports    = uint32(STD)
 	mutex              sync.Mutex
 	timezone           = time.Local
 	l_map              = map[uint32]*_logger{
-		INFO:        {"", os.Stdout, None, true},
-		AUX:         {"", os.Stdout, None, true},
-		AUX2:        {"", os.Stdout, None, true},
-		AUX3:        {"", os.Stdout, None, true},
-		AUX4:        {"", os.Stdout, None, true},
-		ERROR:       {"[ERROR] ", os.Stdout, None, true},
-		WARN:        {"[WARN] ", os.Stdout, None, true},
-		NOTICE:      {"[NOTICE] ", os.Stdout, None, true},
-		DEBUG:       {"[DEBUG] ", None, None, true},
-		TRACE:       {"[TRACE] ", None, None, true},
-		FATAL:       {"[FATAL] ", os.Stdout, None, true},
-		_flash_txt:  {"", os.Stderr, None, false},
-		_print_txt:  {"", os.Stdout, None, false},
-		_stderr_txt: {"", os.Stderr, None, false},
+		INFO:        {"", os.Stdout, None, true, TEXT, TEXT},
+		AUX:         {"", os.Stdout, None, true, TEXT, TEXT},
+		AUX2:        {"", os.Stdout, None, true, TEXT, TEXT},
+		AUX3:        {"", os.Stdout, None, true, TEXT, TEXT},
+		AUX4:        {"", os.Stdout, None, true, TEXT, TEXT},
+		ERROR:       {"[ERROR] ", os.Stdout, None, true, TEXT, TEXT},
+		WARN:        {"[WARN] ", os.Stdout, None, true, TEXT, TEXT},
+		NOTICE:      {"[NOTICE] ", os.Stdout, None, true, TEXT, TEXT},
+		DEBUG:       {"[DEBUG] ", None, None, true, TEXT, TEXT},
+		TRACE:       {"[TRACE] ", None, None, true, TEXT, TEXT},
+		FATAL:       {"[FATAL] ", os.Stdout, None, true, TEXT, TEXT},
+		_flash_txt:  {"", os.Stderr, None, false, TEXT, TEXT},
+		_print_txt:  {"", os.Stdout, None, false, TEXT, TEXT},
+		_stderr_txt: {"", os.Stderr, None, false, TEXT, TEXT},
 	}
 )
 
@@ -95,10 +100,12 @@ func init() {
 }
 
 type _logger struct {
-	prefix  string
-	textout io.Writer
-	fileout io.Writer
-	use_ts  bool
+	prefix      string
+	textout     io.Writer
+	fileout     io.Writer
+	use_ts      bool
+	text_format Format
+	file_format Format
 }
 
 // Creates folders.
@@ -128,7 +135,8 @@ func mkDir(name ...string) (err error) {
 
 // Opens a new log file for writing, max_size is threshold for rotation, max_rotation is number of previous logs to hold on to.
 // Set max_size_mb to 0 to disable file rotation.
-func LogFile(filename string, max_size_mb uint, max_rotation uint) (io.Writer, error) {
+// An optional schedule, ie.. Daily, also rotates the file to a dated name each period, keeping max_periods of previous periods.
+func LogFile(filename string, max_size_mb uint, max_rotation uint, schedule ...Rotation) (io.Writer, error) {
 	max_size := int64(max_size_mb * 1048576)
 	fpath, _ := filepath.Split(filename)
 
@@ -136,7 +144,15 @@ func LogFile(filename string, max_size_mb uint, max_rotation uint) (io.Writer, e
 		return nil, err
 	}
 
-	file, err := wrotate.OpenFile(filename, max_size, max_rotation)
+	var (
+		file io.WriteCloser
+		err  error
+	)
+	if len(schedule) > 0 {
+		file, err = wrotate.OpenDatedFile(filename, max_size, max_rotation, schedule[0].Interval, schedule[0].Offset, schedule[0].Keep)
+	} else {
+		file, err = wrotate.OpenFile(filename, max_size, max_rotation)
+	}
 	if err == nil {
 		Defer(file.Close)
 	}
@@ -195,6 +211,18 @@ func updateLogger(flag uint32, field uint32, input interface{}) {
 				} else {
 					return
 				}
+			case textFormat:
+				if x, ok := input.(Format); ok {
+					v.text_format = x
+				} else {
+					return
+				}
+			case fileFormat:
+				if x, ok := input.(Format); ok {
+					v.file_format = x
+				} else {
+					return
+				}
 			default:
 				return
 			}
@@ -231,12 +259,16 @@ func HideTS(flag ...uint32) {
 }
 
 // Enable a specific logger.
+// Writers added with AddOutput are removed.
 func SetOutput(flag uint32, w io.Writer) {
 	updateLogger(flag, textWriter, w)
+	clearWriters(extra_text, flag)
 }
 
+// Sets file output of specified loggers, writers added with AddFile are removed.
 func SetFile(flag uint32, input io.Writer) {
 	updateLogger(flag, fileWriter, input)
+	clearWriters(extra_file, flag)
 }
 
 // Specify which logs to send to syslog.
@@ -279,8 +311,8 @@ func UTC() {
 }
 
 // Generate TS Bytes
-func genTS(in *[]byte) {
-	CT := time.Now().In(timezone)
+func genTS(in *[]byte, now time.Time) {
+	CT := now.In(timezone)
 
 	year, mon, day := CT.Date()
 	hour, min, sec := CT.Clock()
@@ -312,6 +344,7 @@ func SetPrefix(logger uint32, prefix_str string) {
 }
 
 // Don't log, write text to standard error which will be overwritten on the next output.
+// Text spanning multiple lines is overwritten as a block, each line is cut to the terminal width.
 func Flash(vars ...interface{}) {
 	if Animations {
 		write2log(_flash_txt|_no_logging, vars...)
@@ -377,12 +410,19 @@ func Aux4(vars ...interface{}) {
 
 // Log as Fatal, then quit.
 func Fatal(vars ...interface{}) {
+	fatal(1, nil, nil, vars...)
+}
+
+// Logs fatal with fields, then quits with exit code.
+func fatal(code int, ctx context.Context, fields []field, vars ...interface{}) {
 	if atomic.CompareAndSwapInt32(&fatal_triggered, 0, 1) {
 		// Defer fatal output, so it is the last log entry displayed.
-		write2log(FATAL|_bypass_lock, vars...)
+		writeLog(FATAL|_bypass_lock, ctx, fields, vars...)
+		runFatalHandler(code, vars...)
+		errCode = code
 		signalChan <- os.Kill
 		<-exit_lock
-		os.Exit(1)
+		os.Exit(code)
 	} else {
 		// Catch any other fatals and just let them sit.
 		halt := make(chan struct{})
@@ -431,6 +471,13 @@ func fprintf(buffer io.Writer, vars ...interface{}) {
 
 // Prepares output text and sends to appropriate logging destinations.
 func write2log(flag uint32, vars ...interface{}) {
+	writeLog(flag, nil, nil, vars...)
+}
+
+// Prepares output text with fields and sends to appropriate logging destinations.
+func writeLog(flag uint32, ctx context.Context, fields []field, vars ...interface{}) {
+
+	bypass := flag&_bypass_lock != 0
 
 	if atomic.LoadInt32(&fatal_triggered) == 1 {
 		if flag&_bypass_lock != 0 {
@@ -441,26 +488,54 @@ func write2log(flag uint32, vars ...interface{}) {
 	}
 
 	flag = flag &^ _bypass_lock
+	countEntry(flag)
+
+	// Hand off to the background writer when asynchronous.
+	if queueEntry(flag, ctx, fields, bypass, vars...) {
+		return
+	}
 
 	mutex.Lock()
 	defer mutex.Unlock()
 
+	// Reset buffer.
+	msgBuffer.Reset()
+
+	// Create output string.
+	fprintf(&msgBuffer, vars...)
+
+	writeEntry(flag, ctx, fields, time.Now())
+}
+
+// Sends the message in msgBuffer to the logging destinations, mutex must be held.
+func writeEntry(flag uint32, ctx context.Context, fields []field, now time.Time) {
+	if flag&_no_logging == 0 && suppressDuplicate(flag, fields, now) {
+		return
+	}
+	writeOutput(flag, ctx, fields, now)
+}
+
+// Writes the message in msgBuffer to screen, file and exports.
+func writeOutput(flag uint32, ctx context.Context, fields []field, now time.Time) {
 	logger := l_map[flag&^_no_logging]
 
 	var pre []byte
 
 	if flag&_no_logging != _no_logging {
 		if logger.use_ts {
-			genTS(&pre)
+			genTS(&pre, now)
 		}
 		pre = append(pre, []byte(logger.prefix)[0:]...)
 	}
 
-	// Reset buffer.
-	msgBuffer.Reset()
+	// Copy original output for structured formats.
+	raw := msgBuffer.String()
 
-	// Create output string.
-	fprintf(&msgBuffer, vars...)
+	// Add fields to text output.
+	if len(fields) > 0 {
+		msgBuffer.Truncate(len(strings.TrimRight(raw, "\r\n")))
+		msgBuffer.Write(appendFields(nil, fields))
+	}
 
 	// Copy original output for export.
 	msg := msgBuffer.String()
@@ -479,16 +554,24 @@ func write2log(flag uint32, vars ...interface{}) {
 
 	// Clear out last flash text.
 	if flush_needed && !piped_stderr && ((logger.textout == os.Stdout && !piped_stdout) || logger.textout == os.Stderr) {
-		if flush_line_len < last_flash_len {
-			for i := len(flush_line); i < last_flash_len; i++ {
+		clear_len := last_flash_len
+		if width := termWidth(); clear_len > width {
+			clear_len = width
+		}
+		if flush_line_len < clear_len {
+			for i := len(flush_line); i < clear_len; i++ {
 				flush_line_len++
 				flush_line = append(flush_line[0:], ' ')
 			}
 
 		}
 		fmt.Fprintf(os.Stderr, "\r")
-		fmt.Fprintf(os.Stderr, "%s", string(flush_line[0:last_flash_len]))
+		fmt.Fprintf(os.Stderr, "%s", string(flush_line[0:clear_len]))
 		fmt.Fprintf(os.Stderr, "\r")
+		// Move up and clear any rows above for multi-line or wrapped flash text.
+		for i := flashRows(); i > 1; i-- {
+			fmt.Fprintf(os.Stderr, "\033[A%s\r", string(flush_line[0:clear_len]))
+		}
 		flush_needed = false
 	}
 
@@ -498,36 +581,69 @@ func write2log(flag uint32, vars ...interface{}) {
 	if flag&_flash_txt != 0 {
 		if !piped_stderr {
 			width := termWidth()
-			if utf8.RuneCount(output) > width {
-				output = output[0:width]
+			lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+			// Without escape sequences the cursor can't be moved up, so show only the first line.
+			if no_ansi && len(lines) > 1 {
+				lines = lines[:1]
+			}
+			last_flash_len = 0
+			last_flash_widths = last_flash_widths[:0]
+			for i, line := range lines {
+				lines[i] = truncateWidth(line, width)
+				line_width := textWidth(lines[i])
+				if line_width > last_flash_len {
+					last_flash_len = line_width
+				}
+				last_flash_widths = append(last_flash_widths, line_width)
 			}
-			io.Copy(os.Stderr, bytes.NewReader(output))
+			io.WriteString(os.Stderr, strings.Join(lines, "\n"))
 			flush_needed = true
-			last_flash_len = len(output)
+			last_flash_cols = width
 			return
 		}
 		return
 	}
 
-	io.Copy(logger.textout, bytes.NewReader(output))
 	if flag&_no_logging != 0 {
+		io.Copy(logger.textout, bytes.NewReader(output))
 		return
 	}
 
+	if screen_level&flag == flag {
+		var text []byte
+		if logger.text_format != TEXT {
+			text = genFormat(logger.text_format, flag, now, raw, fields)
+		}
+		for _, w := range append([]io.Writer{logger.textout}, extra_text[flag]...) {
+			if text != nil {
+				io.Copy(w, bytes.NewReader(text))
+			} else {
+				io.Copy(w, bytes.NewReader(colorize(flag, w, output)))
+			}
+		}
+	}
+
 	// Preprend timestamp for file.
-	if !logger.use_ts {
+	if logger.file_format != TEXT {
+		output = genFormat(logger.file_format, flag, now, raw, fields)
+	} else if !logger.use_ts {
 		out_len := len(output)
-		genTS(&output)
+		genTS(&output, now)
 		out := output[out_len:]
 		out = append(out, output[0:out_len]...)
 		output = out
 	}
 
 	// Write to file.
-	_, err := io.Copy(logger.fileout, bytes.NewReader(output))
-	// Launch fatal in a go routine, as the mutex is currently locked.
-	if err != nil && FatalOnFileError {
-		go Fatal(err)
+	var err error
+	if file_level&flag == flag {
+		for _, w := range append([]io.Writer{logger.fileout}, extra_file[flag]...) {
+			_, err = io.Copy(w, bytes.NewReader(output))
+			// Launch fatal in a go routine, as the mutex is currently locked.
+			if err != nil && FatalOnFileError {
+				go Fatal(err)
+			}
+		}
 	}
 
 	if export_syslog != nil && enabled_exports&flag == flag {
@@ -559,4 +675,12 @@ func write2log(flag uint32, vars ...interface{}) {
 			go Fatal(err)
 		}
 	}
+
+	if export_remote != nil && enabled_exports&flag == flag {
+		export_remote.queue(genJSON(flag, now, raw, fields))
+	}
+
+	if export_otel != nil && enabled_exports&flag == flag {
+		exportOTel(flag, ctx, now, raw, fields)
+	}
 }
@@ -0,0 +1,71 @@
+package nfo
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ssh/terminal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var non_interactive struct {
+	enabled bool
+	answers map[string]string
+	mutex   sync.RWMutex
+}
+
+// Resolves prompts without asking when stdin is not a terminal, ie.. for scripted use of interactive tools.
+// Prompts are answered from answers, keyed by the prompt without surrounding spaces or a trailing colon, ie.. "Username" for GetInput("Username: ").
+// Prompts without an answer take their default, prompts that can't be resolved, ie.. NeedAnswer without an answer, are fatal.
+func SetNonInteractive(answers map[string]string) {
+	non_interactive.mutex.Lock()
+	defer non_interactive.mutex.Unlock()
+	non_interactive.enabled = true
+	non_interactive.answers = make(map[string]string)
+	for k, v := range answers {
+		non_interactive.answers[promptKey(k)] = v
+	}
+}
+
+// Returns true if prompts are resolved without asking, see SetNonInteractive.
+func NonInteractive() bool {
+	non_interactive.mutex.RLock()
+	enabled := non_interactive.enabled
+	non_interactive.mutex.RUnlock()
+	return enabled && !terminal.IsTerminal(int(syscall.Stdin))
+}
+
+// Returns the answer given to SetNonInteractive for prompt.
+func Answer(prompt string) (answer string, found bool) {
+	non_interactive.mutex.RLock()
+	defer non_interactive.mutex.RUnlock()
+	answer, found = non_interactive.answers[promptKey(prompt)]
+	return
+}
+
+// Returns prompt without surrounding spaces or a trailing colon.
+func promptKey(prompt string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(prompt), ":"))
+}
+
+// Resolves prompt when non-interactive, with the answer to key or def, the prompt is shown with the answer unless hidden.
+func autoAnswer(key string, prompt string, def string, hidden bool) (string, bool) {
+	if !NonInteractive() {
+		return "", false
+	}
+	answer, found := Answer(key)
+	if !found {
+		answer = def
+	}
+	if hidden {
+		fmt.Printf("%s\n", prompt)
+	} else {
+		fmt.Printf("%s%s\n", prompt, answer)
+	}
+	return answer, true
+}
+
+// Exits when prompt can't be resolved without asking.
+func noAnswer(prompt string, reason interface{}) {
+	Fatal("Cannot answer \"%s\" non-interactively: %v", promptKey(prompt), reason)
+}
@@ -0,0 +1,102 @@
+package nfo
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+var (
+	export_otel OTelLogger
+	otel_ids    func(ctx context.Context) (trace_id, span_id string)
+)
+
+// Log record handed to an OTelLogger, Severity is an OpenTelemetry SeverityNumber.
+type OTelRecord struct {
+	Timestamp    time.Time
+	Severity     int
+	SeverityText string
+	Body         string
+	Attributes   map[string]interface{}
+	TraceID      string // Set when SetTraceIDs is used.
+	SpanID       string // Set when SetTraceIDs is used.
+}
+
+// Receives entries for an OpenTelemetry LoggerProvider, satisfied by a small adapter, ie..
+//
+//	func (b bridge) Emit(ctx context.Context, r nfo.OTelRecord) {
+//		var rec log.Record
+//		rec.SetTimestamp(r.Timestamp)
+//		rec.SetSeverity(log.Severity(r.Severity))
+//		rec.SetSeverityText(r.SeverityText)
+//		rec.SetBody(log.StringValue(r.Body))
+//		b.logger.Emit(ctx, rec)
+//	}
+//
+// ctx is the context given to WithContext, or context.Background(), so the SDK can correlate the record with the active span.
+type OTelLogger interface {
+	Emit(ctx context.Context, record OTelRecord)
+}
+
+// Sends entries of loggers enabled with EnableExport to an OpenTelemetry logger.
+func HookOTel(logger OTelLogger) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	export_otel = logger
+}
+
+// Disconnect from OpenTelemetry logger.
+func UnhookOTel() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	export_otel = nil
+}
+
+// Sets a function to read trace and span IDs from the context of entries, ie.. from trace.SpanContextFromContext.
+func SetTraceIDs(fn func(ctx context.Context) (trace_id, span_id string)) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	otel_ids = fn
+}
+
+// Returns the OpenTelemetry SeverityNumber of a logger.
+func otelSeverity(flag uint32) int {
+	switch flag {
+	case TRACE:
+		return 1
+	case DEBUG:
+		return 5
+	case NOTICE:
+		return 10
+	case WARN:
+		return 13
+	case ERROR:
+		return 17
+	case FATAL:
+		return 21
+	}
+	return 9
+}
+
+// Forwards an entry to the OpenTelemetry logger, mutex must be held.
+func exportOTel(flag uint32, ctx context.Context, now time.Time, msg string, fields []field) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	record := OTelRecord{
+		Timestamp:    now,
+		Severity:     otelSeverity(flag),
+		SeverityText: strings.ToUpper(levelName(flag)),
+		Body:         strings.TrimRight(msg, "\r\n"),
+	}
+	if len(fields) > 0 {
+		record.Attributes = make(map[string]interface{})
+		for _, f := range fields {
+			record.Attributes[f.key] = f.value
+		}
+	}
+	if otel_ids != nil {
+		record.TraceID, record.SpanID = otel_ids(ctx)
+	}
+	export_otel.Emit(ctx, record)
+}
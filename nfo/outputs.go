@@ -0,0 +1,94 @@
+package nfo
+
+import (
+	"io"
+	"reflect"
+)
+
+// Additional writers of loggers, added with AddOutput and AddFile.
+var (
+	extra_text = make(map[uint32][]io.Writer)
+	extra_file = make(map[uint32][]io.Writer)
+)
+
+// Adds a writer to the screen output of specified loggers, alongside any existing output.
+func AddOutput(flag uint32, w io.Writer) {
+	addWriter(extra_text, flag, w)
+}
+
+// Removes a writer from the screen output of specified loggers.
+func RemoveOutput(flag uint32, w io.Writer) {
+	removeWriter(extra_text, flag, w, func(l *_logger) *io.Writer { return &l.textout })
+}
+
+// Adds a writer to the file output of specified loggers, alongside any existing file, write errors are handled as with SetFile.
+func AddFile(flag uint32, w io.Writer) {
+	addWriter(extra_file, flag, w)
+}
+
+// Removes a writer from the file output of specified loggers.
+func RemoveFile(flag uint32, w io.Writer) {
+	removeWriter(extra_file, flag, w, func(l *_logger) *io.Writer { return &l.fileout })
+}
+
+// Adds w to the writers of each logger in flag.
+func addWriter(extra map[uint32][]io.Writer, flag uint32, w io.Writer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for k := range l_map {
+		if flag&k == k {
+			extra[k] = append(extra[k], w)
+		}
+	}
+}
+
+// Removes w from the writers of each logger in flag, a primary output of w is replaced with None.
+func removeWriter(extra map[uint32][]io.Writer, flag uint32, w io.Writer, primary func(l *_logger) *io.Writer) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for k, v := range l_map {
+		if flag&k != k {
+			continue
+		}
+		if p := primary(v); sameWriter(*p, w) {
+			*p = None
+		}
+		var writers []io.Writer
+		for _, x := range extra[k] {
+			if !sameWriter(x, w) {
+				writers = append(writers, x)
+			}
+		}
+		extra[k] = writers
+	}
+}
+
+// Returns true if a and b are the same writer, writers of types that can't be compared with == match by pointer, ie.. a func or map based writer.
+func sameWriter(a, b io.Writer) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	if t == nil || t.Comparable() {
+		return a == b
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Func, reflect.Map:
+		return va.Pointer() == vb.Pointer()
+	case reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+	return false
+}
+
+// Clears the writers added to each logger in flag.
+func clearWriters(extra map[uint32][]io.Writer, flag uint32) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for k := range l_map {
+		if flag&k == k {
+			delete(extra, k)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package nfo
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// RecoverAndLog and GoSafe log panics as Fatal, starting shutdown, rather than as errors.
+var FatalOnPanic = false
+
+// Recovers a panic and logs it with the stack, must be deferred, ie.. defer nfo.RecoverAndLog("worker %d", id)
+// extra is formatted as with Log and added to the message.
+func RecoverAndLog(extra ...interface{}) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	msg := fmt.Sprintf("(panic) %v", r)
+	if len(extra) > 0 {
+		msg = fmt.Sprintf("(panic) %s: %v", Stringer(extra...), r)
+	}
+	msg = fmt.Sprintf("%s\n%s", msg, string(debug.Stack()))
+
+	if FatalOnPanic {
+		Fatal(msg)
+	} else {
+		Err(msg)
+	}
+}
+
+// Runs fn in a goroutine, panics are recovered and logged with RecoverAndLog.
+func GoSafe(fn func()) {
+	go func() {
+		defer RecoverAndLog()
+		fn()
+	}()
+}
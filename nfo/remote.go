@@ -0,0 +1,296 @@
+package nfo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/cmcoffee/snugforge/wrotate"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var export_remote *Remote
+
+const (
+	remote_spill_mb       = 10 // Size of spill file before rotation.
+	remote_spill_rotation = 3  // Rotated spill files kept.
+)
+
+// Ships log entries as JSON to a TCP endpoint or HTTP collector, see HookRemote.
+type Remote struct {
+	BatchSize     int           // Maximum entries sent at once. (Default: 100)
+	FlushInterval time.Duration // Maximum time an entry waits before being sent. (Default: 5s)
+	MaxBackoff    time.Duration // Maximum wait between attempts to reach the remote. (Default: 1m)
+	Timeout       time.Duration // Timeout for connecting and sending. (Default: 30s)
+	address       string
+	spill_file    string
+	spill         io.WriteCloser
+	conn          net.Conn
+	client        *http.Client
+	mutex         sync.Mutex
+	pending       [][]byte
+	spilled       bool
+	backoff       time.Duration
+	retry_at      time.Time
+	wake          chan struct{}
+	done          chan struct{}
+	closed        chan struct{}
+}
+
+// Creates a Remote for address, ie.. tcp://logs.example.com:5170 or https://logs.example.com/ingest.
+// TCP endpoints receive newline delimited JSON, HTTP collectors receive each batch POSTed as a JSON array.
+// While the remote is down, entries are written to spill_file, which is rotated by size and resent once the remote is back.
+func NewRemote(address string, spill_file string) (*Remote, error) {
+	switch {
+	case strings.HasPrefix(address, "tcp://"):
+	case strings.HasPrefix(address, "http://"), strings.HasPrefix(address, "https://"):
+	default:
+		return nil, fmt.Errorf("unsupported remote address %s, expected tcp://, http:// or https://", address)
+	}
+
+	if spill_file != "" {
+		fpath, _ := filepath.Split(spill_file)
+		if err := mkDir(fpath); err != nil {
+			return nil, err
+		}
+	}
+
+	R := &Remote{
+		BatchSize:     100,
+		FlushInterval: 5 * time.Second,
+		MaxBackoff:    time.Minute,
+		Timeout:       30 * time.Second,
+		address:       address,
+		spill_file:    spill_file,
+		client:        new(http.Client),
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		closed:        make(chan struct{}),
+		spilled:       spill_file != "",
+	}
+	go R.run()
+	Defer(R.Close)
+	return R, nil
+}
+
+// Sends entries of loggers enabled with EnableExport to remote.
+func HookRemote(remote *Remote) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	export_remote = remote
+}
+
+// Disconnect from remote.
+func UnhookRemote() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	export_remote = nil
+}
+
+// Sends any pending entries and stops the Remote.
+func (R *Remote) Close() error {
+	select {
+	case <-R.done:
+	default:
+		close(R.done)
+	}
+	<-R.closed
+	return nil
+}
+
+// Adds an entry to be sent.
+func (R *Remote) queue(entry []byte) {
+	R.mutex.Lock()
+	R.pending = append(R.pending, entry)
+	full := len(R.pending) >= R.BatchSize
+	R.mutex.Unlock()
+
+	if full {
+		select {
+		case R.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Sends batches until closed.
+func (R *Remote) run() {
+	defer close(R.closed)
+
+	interval := R.FlushInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-R.wake:
+		case <-ticker.C:
+		case <-R.done:
+			R.flush()
+			if R.conn != nil {
+				R.conn.Close()
+			}
+			if R.spill != nil {
+				R.spill.Close()
+			}
+			return
+		}
+		R.flush()
+	}
+}
+
+// Sends pending entries, entries are spilled to disk if the remote is unavailable.
+func (R *Remote) flush() {
+	for {
+		R.mutex.Lock()
+		n := len(R.pending)
+		if R.BatchSize > 0 && n > R.BatchSize {
+			n = R.BatchSize
+		}
+		batch := R.pending[:n]
+		R.pending = R.pending[n:]
+		R.mutex.Unlock()
+
+		if len(batch) == 0 {
+			return
+		}
+
+		if time.Now().Before(R.retry_at) || R.resend() != nil || R.send(batch) != nil {
+			R.spillBatch(batch)
+		}
+	}
+}
+
+// Sends a batch to the remote, increasing backoff on failure.
+func (R *Remote) send(batch [][]byte) (err error) {
+	if strings.HasPrefix(R.address, "tcp://") {
+		err = R.sendTCP(batch)
+	} else {
+		err = R.sendHTTP(batch)
+	}
+	if err != nil {
+		if R.backoff == 0 {
+			R.backoff = time.Second
+		} else if R.backoff *= 2; R.MaxBackoff > 0 && R.backoff > R.MaxBackoff {
+			R.backoff = R.MaxBackoff
+		}
+		R.retry_at = time.Now().Add(R.backoff)
+		return err
+	}
+	R.backoff = 0
+	return nil
+}
+
+// Writes a batch as newline delimited JSON, reconnecting as needed.
+func (R *Remote) sendTCP(batch [][]byte) (err error) {
+	if R.conn == nil {
+		R.conn, err = net.DialTimeout("tcp", strings.TrimPrefix(R.address, "tcp://"), R.Timeout)
+		if err != nil {
+			return err
+		}
+	}
+	if R.Timeout > 0 {
+		R.conn.SetWriteDeadline(time.Now().Add(R.Timeout))
+	}
+	if _, err = R.conn.Write(bytes.Join(batch, nil)); err != nil {
+		R.conn.Close()
+		R.conn = nil
+	}
+	return err
+}
+
+// POSTs a batch as a JSON array.
+func (R *Remote) sendHTTP(batch [][]byte) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, entry := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(bytes.TrimRight(entry, "\n"))
+	}
+	body.WriteByte(']')
+
+	R.client.Timeout = R.Timeout
+	resp, err := R.client.Post(R.address, "application/json", &body)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("remote %s returned %s", R.address, resp.Status)
+	}
+	return nil
+}
+
+// Writes a batch to the spill file, entries are dropped if there is no spill file.
+func (R *Remote) spillBatch(batch [][]byte) {
+	if R.spill_file == "" {
+		return
+	}
+	R.spilled = true
+	if R.spill == nil {
+		f, err := wrotate.OpenFile(R.spill_file, remote_spill_mb*1048576, remote_spill_rotation)
+		if err != nil {
+			return
+		}
+		R.spill = f
+	}
+	for _, entry := range batch {
+		R.spill.Write(entry)
+	}
+}
+
+// Resends spilled entries, oldest first, removing each spill file once sent.
+// Entries of a spill file may be sent again if the remote fails part way through it.
+func (R *Remote) resend() error {
+	if !R.spilled {
+		return nil
+	}
+	if R.spill != nil {
+		R.spill.Close()
+		R.spill = nil
+	}
+
+	for i := remote_spill_rotation; i >= 0; i-- {
+		name := R.spill_file
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", name, i)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+
+		var batch [][]byte
+		for _, entry := range bytes.SplitAfter(data, []byte("\n")) {
+			if len(bytes.TrimSpace(entry)) == 0 {
+				continue
+			}
+			batch = append(batch, entry)
+			if len(batch) == R.BatchSize || R.BatchSize <= 0 {
+				if err := R.send(batch); err != nil {
+					return err
+				}
+				batch = nil
+			}
+		}
+		if len(batch) > 0 {
+			if err := R.send(batch); err != nil {
+				return err
+			}
+		}
+		os.Remove(name)
+	}
+	R.spilled = false
+	return nil
+}
@@ -0,0 +1,38 @@
+package nfo
+
+import (
+	"golang.org/x/crypto/ssh/terminal"
+	"sync/atomic"
+	"syscall"
+)
+
+// Terminal size, kept current by watchResize.
+var term_cols, term_rows int64
+
+func init() {
+	updateTermSize()
+	if !terminal.IsTerminal(int(syscall.Stderr)) {
+		return
+	}
+	watchResize()
+}
+
+// Measures the terminal.
+func updateTermSize() {
+	cols, rows, _ := terminal.GetSize(int(syscall.Stderr))
+	atomic.StoreInt64(&term_cols, int64(cols))
+	atomic.StoreInt64(&term_rows, int64(rows))
+}
+
+// Returns the rows taken by the last flash text, lines wrap when the terminal was made narrower since.
+func flashRows() (rows int) {
+	width := termWidth()
+	for _, w := range last_flash_widths {
+		if width < last_flash_cols && w > width+1 {
+			rows += (w + width) / (width + 1)
+		} else {
+			rows++
+		}
+	}
+	return rows
+}
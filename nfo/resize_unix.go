@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package nfo
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Measures the terminal again when resized.
+func watchResize() {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	go func() {
+		for range resized {
+			updateTermSize()
+		}
+	}()
+}
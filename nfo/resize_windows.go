@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package nfo
+
+import (
+	"time"
+)
+
+// Measures the console again every half second, as Windows has no resize signal.
+func watchResize() {
+	go func() {
+		for {
+			time.Sleep(500 * time.Millisecond)
+			updateTermSize()
+		}
+	}()
+}
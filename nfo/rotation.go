@@ -0,0 +1,20 @@
+package nfo
+
+import "time"
+
+// Schedule for rotating log files to dated names, see LogFile.
+type Rotation struct {
+	Interval time.Duration // Length of each period, ie.. time.Hour
+	Offset   time.Duration // Start of the first period past midnight, ie.. 2*time.Hour for 02:00.
+	Keep     uint          // Number of previous periods to hold on to, 0 keeps all.
+}
+
+var (
+	Hourly = Rotation{Interval: time.Hour}      // Rotate at the top of each hour, ie.. app.log.2006-01-02T15-00
+	Daily  = Rotation{Interval: 24 * time.Hour} // Rotate at midnight, ie.. app.log.2006-01-02
+)
+
+// Rotates daily at hour and minute, keeping keep days of previous logs, ie.. DailyAt(2, 30, 7)
+func DailyAt(hour, minute int, keep uint) Rotation {
+	return Rotation{24 * time.Hour, time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, keep}
+}
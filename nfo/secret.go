@@ -0,0 +1,54 @@
+package nfo
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Gets a new secret, ie.. when setting a password, asking again until it is at least min_len characters and passes rules.
+// When confirm is set, the secret is asked for twice and must match.
+// Errors of rules are shown after "Secret", ie.. fmt.Errorf("must contain at least one digit"), see SecretHas.
+func GetNewSecret(prompt string, min_len int, confirm bool, rules ...func(secret string) error) string {
+	for {
+		secret := GetSecret(prompt)
+
+		if utf8.RuneCountInString(secret) < min_len {
+			if NonInteractive() {
+				noAnswer(prompt, fmt.Sprintf("must be at least %d characters", min_len))
+			}
+			fmt.Printf("Secret must be at least %d characters, please try again.\n", min_len)
+			continue
+		}
+
+		var err error
+		for _, rule := range rules {
+			if err = rule(secret); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			if NonInteractive() {
+				noAnswer(prompt, err)
+			}
+			fmt.Printf("Secret %s, please try again.\n", err)
+			continue
+		}
+
+		if confirm && !NonInteractive() && GetSecret(fmt.Sprintf("Confirm %s", prompt)) != secret {
+			fmt.Printf("Entries do not match, please try again.\n")
+			continue
+		}
+		return secret
+	}
+}
+
+// Rule for GetNewSecret requiring at least one of chars, ie.. SecretHas("digit", "0123456789")
+func SecretHas(name string, chars string) func(secret string) error {
+	return func(secret string) error {
+		if !strings.ContainsAny(secret, chars) {
+			return fmt.Errorf("must contain at least one %s", name)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package nfo
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var shutdown struct {
+	mutex    sync.Mutex
+	timeout  time.Duration
+	running  string         // Deferred function currently running.
+	blockers map[string]int // Functions which called BlockShutdown, and have yet to unblock.
+}
+
+// Sets the time allowed for deferred functions and BlockShutdown holders to finish once shutdown begins, 0 waits forever.
+// On timeout, the functions still running are logged and the application exits.
+func SetShutdownTimeout(d time.Duration) {
+	shutdown.mutex.Lock()
+	defer shutdown.mutex.Unlock()
+	shutdown.timeout = d
+}
+
+// Returns the name of function fn.
+func funcName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
+
+// Returns the name of the function skip frames up the stack.
+func callerName(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	if f := runtime.FuncForPC(pc); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
+
+// Tracks BlockShutdown holders, unblocks are matched to the same function where possible.
+func trackBlocker(name string, n int) {
+	shutdown.mutex.Lock()
+	defer shutdown.mutex.Unlock()
+	if shutdown.blockers == nil {
+		shutdown.blockers = make(map[string]int)
+	}
+	if n < 0 && shutdown.blockers[name] == 0 {
+		for k := range shutdown.blockers {
+			name = k
+			break
+		}
+	}
+	if shutdown.blockers[name] += n; shutdown.blockers[name] <= 0 {
+		delete(shutdown.blockers, name)
+	}
+}
+
+// Records the deferred function running during shutdown.
+func setRunning(name string) {
+	shutdown.mutex.Lock()
+	defer shutdown.mutex.Unlock()
+	shutdown.running = name
+}
+
+// Starts the shutdown timer, if a timeout is set.
+func startShutdownTimer() {
+	shutdown.mutex.Lock()
+	timeout := shutdown.timeout
+	shutdown.mutex.Unlock()
+	if timeout <= 0 {
+		return
+	}
+	time.AfterFunc(timeout, func() {
+		shutdown.mutex.Lock()
+		var stuck []string
+		if shutdown.running != "" {
+			stuck = append(stuck, fmt.Sprintf("deferred %s", shutdown.running))
+		}
+		var blockers []string
+		for name, n := range shutdown.blockers {
+			blockers = append(blockers, fmt.Sprintf("BlockShutdown by %s (%d)", name, n))
+		}
+		sort.Strings(blockers)
+		stuck = append(stuck, blockers...)
+		shutdown.mutex.Unlock()
+
+		msg := fmt.Sprintf("Shutdown timed out after %s.", timeout)
+		if len(stuck) > 0 {
+			msg = fmt.Sprintf("Shutdown timed out after %s, waiting on: %s", timeout, strings.Join(stuck, ", "))
+		}
+
+		// Don't let a stuck logger hold up the exit.
+		logged := make(chan struct{})
+		go func() {
+			write2log(ERROR|_bypass_lock, msg)
+			close(logged)
+		}()
+		select {
+		case <-logged:
+		case <-time.After(time.Second):
+		}
+
+		if errCode == 0 {
+			os.Exit(1)
+		}
+		os.Exit(errCode)
+	})
+}
@@ -0,0 +1,107 @@
+package nfo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Active spinners, shown one per line.
+var spinners struct {
+	mutex   sync.Mutex
+	active  []*Spinner
+	running bool
+}
+
+// Wait indicator scoped to a task, independent of PleaseWait, see NewSpinner.
+type Spinner struct {
+	message func() string
+	frames  []string
+	frame   int
+	mutex   sync.Mutex
+}
+
+// Creates a Spinner showing frames next to message, ie.. NewSpinner(func() string { return "Indexing ..." }, []string{"\\", "|", "/", "-"})
+// Multiple spinners may be started at once, each is shown on its own line while PleaseWait is held back.
+func NewSpinner(message func() string, frames []string) *Spinner {
+	if len(frames) == 0 {
+		frames = []string{"\\", "|", "/", "-"}
+	}
+	return &Spinner{
+		message: message,
+		frames:  frames,
+	}
+}
+
+// Shows the spinner.
+func (S *Spinner) Start() {
+	spinners.mutex.Lock()
+	defer spinners.mutex.Unlock()
+
+	for _, v := range spinners.active {
+		if v == S {
+			return
+		}
+	}
+	spinners.active = append(spinners.active, S)
+
+	if !spinners.running {
+		spinners.running = true
+		PleaseWait.flag.Set(spinner_active)
+		go showSpinners()
+	}
+}
+
+// Hides the spinner.
+func (S *Spinner) Stop() {
+	spinners.mutex.Lock()
+	defer spinners.mutex.Unlock()
+
+	for i, v := range spinners.active {
+		if v == S {
+			spinners.active = append(spinners.active[:i], spinners.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// Changes the message of the spinner.
+func (S *Spinner) Update(message func() string) {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+	S.message = message
+}
+
+// Returns the next frame of the spinner with its message.
+func (S *Spinner) next() string {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+	frame := S.frames[S.frame%len(S.frames)]
+	S.frame++
+	return fmt.Sprintf("%s %s", frame, S.message())
+}
+
+// Displays active spinners until all are stopped, transfers take precedence.
+func showSpinners() {
+	for {
+		spinners.mutex.Lock()
+		if len(spinners.active) == 0 {
+			spinners.running = false
+			PleaseWait.flag.Unset(spinner_active)
+			spinners.mutex.Unlock()
+			Flash("")
+			return
+		}
+		var lines []string
+		for _, v := range spinners.active {
+			lines = append(lines, v.next())
+		}
+		spinners.mutex.Unlock()
+
+		if !PleaseWait.flag.Has(transfer_monitor_active) {
+			Flash("%s", strings.Join(lines, "\n"))
+		}
+		time.Sleep(125 * time.Millisecond)
+	}
+}
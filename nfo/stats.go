@@ -0,0 +1,54 @@
+package nfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"sync/atomic"
+)
+
+// Loggers in order of reporting.
+var stat_levels = []uint32{FATAL, ERROR, WARN, NOTICE, INFO, DEBUG, TRACE, AUX, AUX2, AUX3, AUX4}
+
+// Entries logged, by bit of logger flag.
+var stat_counts [32]uint64
+
+// Counts an entry for logger flag.
+func countEntry(flag uint32) {
+	if flag&ALL == flag && flag != 0 {
+		atomic.AddUint64(&stat_counts[bits.TrailingZeros32(flag)], 1)
+	}
+}
+
+// Returns a snapshot of entries logged per level, keyed by level name, ie.. "error"
+func Stats() map[string]uint64 {
+	output := make(map[string]uint64)
+	for _, l := range stat_levels {
+		output[levelName(l)] = atomic.LoadUint64(&stat_counts[bits.TrailingZeros32(l)])
+	}
+	return output
+}
+
+// Stats as an expvar.Var, ie.. expvar.Publish("nfo", nfo.StatsVar)
+var StatsVar statsVar
+
+type statsVar struct{}
+
+// Returns Stats as JSON.
+func (statsVar) String() string {
+	data, _ := json.Marshal(Stats())
+	return string(data)
+}
+
+// Serves Stats in the Prometheus text format, ie.. http.Handle("/metrics", nfo.StatsHandler())
+func StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprintf(w, "# HELP nfo_log_entries_total Log entries written, by level.\n")
+		fmt.Fprintf(w, "# TYPE nfo_log_entries_total counter\n")
+		for _, l := range stat_levels {
+			fmt.Fprintf(w, "nfo_log_entries_total{level=%q} %d\n", levelName(l), atomic.LoadUint64(&stat_counts[bits.TrailingZeros32(l)]))
+		}
+	})
+}
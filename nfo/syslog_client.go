@@ -0,0 +1,246 @@
+package nfo
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const syslog_queue_size = 1000 // Messages held while the syslog server is slow or unreachable.
+
+// Syslog severities.
+const (
+	sev_emerg = iota
+	sev_alert
+	sev_crit
+	sev_err
+	sev_warning
+	sev_notice
+	sev_info
+	sev_debug
+)
+
+// RFC 5424 syslog client, implements SyslogWriter for use with HookSyslog.
+type Syslog struct {
+	Facility       int           // Syslog facility, ie.. 1 for user, 16 through 23 for local0 through local7. (Default: 1)
+	AppName        string        // APP-NAME of messages. (Default: program name)
+	Hostname       string        // HOSTNAME of messages. (Default: os.Hostname)
+	StructuredData string        // SD-ELEMENTs added to messages, ie.. [origin software="tool" swVersion="1.0"]
+	Timeout        time.Duration // Timeout for connecting and sending. (Default: 30s)
+	network        string
+	address        string
+	tls_config     *tls.Config
+	conn           net.Conn
+	mutex          sync.Mutex
+	err            error
+	dropped        int64
+	queue          chan string
+	done           chan struct{}
+	closed         chan struct{}
+}
+
+// Connects to a syslog server over "udp", "tcp" or "tls", ie.. DialSyslog("tls", "logs.example.com:6514", nil)
+// TCP and TLS messages are framed by octet counting, per RFC 5425.
+// Messages are sent in the background, if the server falls behind, messages beyond the queue are dropped and counted in a later warning.
+func DialSyslog(network, address string, tls_config *tls.Config) (*Syslog, error) {
+	switch network {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %s, expected udp, tcp or tls", network)
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	S := &Syslog{
+		Facility:   1,
+		AppName:    filepath.Base(os.Args[0]),
+		Hostname:   hostname,
+		Timeout:    30 * time.Second,
+		network:    network,
+		address:    address,
+		tls_config: tls_config,
+		queue:      make(chan string, syslog_queue_size),
+		done:       make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+	if err := S.connect(); err != nil {
+		return nil, err
+	}
+	go S.run()
+	Defer(S.Close)
+	return S, nil
+}
+
+// Returns Timeout, or the default if unset.
+func (S *Syslog) timeout() time.Duration {
+	if S.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return S.Timeout
+}
+
+// Opens the connection to the syslog server.
+func (S *Syslog) connect() (err error) {
+	if S.conn != nil {
+		S.conn.Close()
+		S.conn = nil
+	}
+	dialer := &net.Dialer{Timeout: S.timeout()}
+	if S.network == "tls" {
+		S.conn, err = tls.DialWithDialer(dialer, "tcp", S.address, S.tls_config)
+	} else {
+		S.conn, err = dialer.Dial(S.network, S.address)
+	}
+	return err
+}
+
+// Sends queued messages, for up to Timeout, and closes the connection to the syslog server, later messages are discarded.
+func (S *Syslog) Close() error {
+	S.mutex.Lock()
+	select {
+	case <-S.done:
+	default:
+		close(S.done)
+	}
+	S.mutex.Unlock()
+	<-S.closed
+	return nil
+}
+
+// Sends queued messages until closed.
+func (S *Syslog) run() {
+	defer close(S.closed)
+	for {
+		select {
+		case line := <-S.queue:
+			S.send(line)
+		case <-S.done:
+			deadline := time.Now().Add(S.timeout())
+			for {
+				select {
+				case line := <-S.queue:
+					if time.Now().Before(deadline) {
+						S.send(line)
+					}
+				default:
+					if S.conn != nil {
+						S.conn.Close()
+						S.conn = nil
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// Returns value, or "-" if empty, limited to max printable characters without spaces.
+func syslogHeader(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	if len(value) > max {
+		value = value[:max]
+	}
+	return value
+}
+
+// Returns msg with severity as an RFC 5424 line, octet counted for stream connections.
+func (S *Syslog) format(severity int, msg string) string {
+	sd := S.StructuredData
+	if sd == "" {
+		sd = "-"
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s %s %d - %s %s",
+		S.Facility*8+severity,
+		time.Now().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeader(S.Hostname, 255),
+		syslogHeader(S.AppName, 48),
+		os.Getpid(),
+		sd,
+		strings.TrimRight(msg, "\r\n"))
+
+	if S.network != "udp" {
+		line = fmt.Sprintf("%d %s", len(line), line)
+	}
+	return line
+}
+
+// Queues msg with severity, returning the error of an earlier failed send, if any.
+func (S *Syslog) write(severity int, msg string) (err error) {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+
+	err, S.err = S.err, nil
+
+	select {
+	case <-S.done:
+		return err
+	default:
+	}
+
+	select {
+	case S.queue <- S.format(severity, msg):
+	default:
+		atomic.AddInt64(&S.dropped, 1)
+	}
+	return err
+}
+
+// Sends line, reconnecting once if a stream connection was lost, preceded by a warning of any dropped messages.
+func (S *Syslog) send(line string) {
+	if n := atomic.SwapInt64(&S.dropped, 0); n > 0 {
+		if S.transmit(S.format(sev_warning, fmt.Sprintf("syslog queue full, dropped %d messages.", n))) != nil {
+			atomic.AddInt64(&S.dropped, n)
+		}
+	}
+	if err := S.transmit(line); err != nil {
+		S.mutex.Lock()
+		S.err = err
+		S.mutex.Unlock()
+	}
+}
+
+// Writes line to the syslog server, with a deadline so a stalled server can't hold up the queue.
+func (S *Syslog) transmit(line string) (err error) {
+	for i := 0; i < 2; i++ {
+		if S.conn == nil {
+			if err = S.connect(); err != nil {
+				continue
+			}
+		}
+		S.conn.SetWriteDeadline(time.Now().Add(S.timeout()))
+		if _, err = S.conn.Write([]byte(line)); err == nil {
+			return nil
+		}
+		S.conn.Close()
+		S.conn = nil
+	}
+	return err
+}
+
+// SyslogWriter methods, each sends msg at the named severity.
+func (S *Syslog) Emerg(msg string) error   { return S.write(sev_emerg, msg) }
+func (S *Syslog) Alert(msg string) error   { return S.write(sev_alert, msg) }
+func (S *Syslog) Crit(msg string) error    { return S.write(sev_crit, msg) }
+func (S *Syslog) Err(msg string) error     { return S.write(sev_err, msg) }
+func (S *Syslog) Warning(msg string) error { return S.write(sev_warning, msg) }
+func (S *Syslog) Notice(msg string) error  { return S.write(sev_notice, msg) }
+func (S *Syslog) Info(msg string) error    { return S.write(sev_info, msg) }
+func (S *Syslog) Debug(msg string) error   { return S.write(sev_debug, msg) }
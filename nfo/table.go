@@ -0,0 +1,145 @@
+package nfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column alignments of a Table.
+const (
+	AlignLeft = iota
+	AlignRight
+)
+
+const table_min_width = 3 // Narrowest a column is cut to when fitting MaxWidth.
+
+// Text output in aligned columns, ie.. for list style command output, see NewTable.
+type Table struct {
+	MaxWidth int // Maximum width of the table, the widest columns are cut to fit. (Default: terminal width, no limit when not a terminal)
+	headers  []string
+	align    map[int]int
+	rows     [][]string
+}
+
+// Creates a Table with headers, headers may be left out for a table without them.
+func NewTable(headers ...string) *Table {
+	return &Table{
+		headers: headers,
+		align:   make(map[int]int),
+	}
+}
+
+// Sets the alignment of column, starting at 0, ie.. T.Align(2, AlignRight) for a column of numbers.
+func (T *Table) Align(column int, align int) {
+	T.align[column] = align
+}
+
+// Adds a row, each cell is formatted with %v.
+func (T *Table) AddRow(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, v := range cells {
+		row[i] = strings.NewReplacer("\r", "", "\n", " ", "\t", " ").Replace(fmt.Sprintf("%v", v))
+	}
+	T.rows = append(T.rows, row)
+}
+
+// Returns the widths of each column, cut down to fit MaxWidth.
+func (T *Table) widths() []int {
+	var widths []int
+	measure := func(row []string) {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := textWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	measure(T.headers)
+	for _, row := range T.rows {
+		measure(row)
+	}
+
+	max_width := T.MaxWidth
+	if max_width <= 0 {
+		max_width = termWidth()
+	}
+	if max_width <= 0 || len(widths) == 0 {
+		return widths
+	}
+
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for ; total > max_width; total-- {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= table_min_width {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// Returns row as a line with cells aligned to widths.
+func (T *Table) line(row []string, widths []int) string {
+	cells := make([]string, len(widths))
+	for i, width := range widths {
+		var cell string
+		if i < len(row) {
+			cell = row[i]
+		}
+		if textWidth(cell) > width {
+			if width > 2 {
+				cell = truncateWidth(cell, width-2) + ".."
+			} else {
+				cell = truncateWidth(cell, width)
+			}
+		}
+		if T.align[i] == AlignRight {
+			cell = padWidth(cell, width)
+		} else if i < len(widths)-1 {
+			cell = cell + strings.Repeat(" ", width-textWidth(cell))
+		}
+		cells[i] = cell
+	}
+	return strings.TrimRight(strings.Join(cells, "  "), " ")
+}
+
+// Returns the table as text, headers are underlined.
+func (T *Table) String() string {
+	widths := T.widths()
+
+	var lines []string
+	if len(T.headers) > 0 {
+		lines = append(lines, T.line(T.headers, widths))
+		rule := make([]string, len(widths))
+		for i, w := range widths {
+			rule[i] = strings.Repeat("-", w)
+		}
+		lines = append(lines, strings.Join(rule, "  "))
+	}
+	for _, row := range T.rows {
+		lines = append(lines, T.line(row, widths))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Prints the table to standard out.
+func (T *Table) Stdout() {
+	Stdout("%s", T.String())
+}
+
+// Logs the table as Info, one entry per line.
+func (T *Table) Log() {
+	for _, line := range strings.Split(T.String(), "\n") {
+		Log("%s", line)
+	}
+}
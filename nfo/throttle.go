@@ -0,0 +1,65 @@
+package nfo
+
+import (
+	"sync"
+	"time"
+)
+
+// Bandwidth limited ReadSeekCloser.
+type throttle struct {
+	ReadSeekCloser
+	limit int64
+	start time.Time
+	count int64
+	mutex sync.Mutex
+}
+
+// Limits reads of rs to bytes_per_sec, 0 or less for no limit.
+// Wrap the source given to TransferMonitor for the displayed rate to follow, ie.. TransferMonitor(name, size, 0, Throttle(f, 1048576))
+func Throttle(rs ReadSeekCloser, bytes_per_sec int64) ReadSeekCloser {
+	return &throttle{ReadSeekCloser: rs, limit: bytes_per_sec}
+}
+
+// Throttled Reader, reads are split to at most a tenth of a second of bandwidth.
+func (T *throttle) Read(p []byte) (n int, err error) {
+	if T.limit <= 0 {
+		return T.ReadSeekCloser.Read(p)
+	}
+
+	T.mutex.Lock()
+	defer T.mutex.Unlock()
+
+	chunk := T.limit / 10
+	if chunk < 1 {
+		chunk = 1
+	}
+	if int64(len(p)) > chunk {
+		p = p[:chunk]
+	}
+
+	if T.start.IsZero() {
+		T.start = time.Now()
+	}
+
+	n, err = T.ReadSeekCloser.Read(p)
+	T.count += int64(n)
+
+	// Wait until the bytes read fit within the limit, starting over when falling behind, ie.. after the reader was idle.
+	wait := time.Until(T.start.Add(time.Duration(float64(T.count) / float64(T.limit) * float64(time.Second))))
+	if wait > 0 {
+		time.Sleep(wait)
+	} else if wait < -time.Second {
+		T.start = time.Now()
+		T.count = 0
+	}
+	return
+}
+
+// Wrapped Seeker, restarts the rate limit.
+func (T *throttle) Seek(offset int64, whence int) (int64, error) {
+	T.mutex.Lock()
+	T.start = time.Time{}
+	T.count = 0
+	T.mutex.Unlock()
+	return T.ReadSeekCloser.Seek(offset, whence)
+}
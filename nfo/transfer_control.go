@@ -0,0 +1,69 @@
+package nfo
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Transfer returned by TransferGroup.Add, and by TransferMonitor as a ReadSeekCloser.
+type Transfer interface {
+	ReadSeekCloser
+	Pause()  // Hides the transfer from display, time and bytes while paused do not count toward its rate.
+	Resume() // Shows a paused transfer again.
+	Cancel() // Marks the transfer aborted, logging a cancelled summary in place of the completed one.
+}
+
+// Pauses the transfer.
+func (tm *tmon) Pause() {
+	tm.rate_lock.Lock()
+	defer tm.rate_lock.Unlock()
+	if tm.flag.Has(trans_paused) {
+		return
+	}
+	tm.pause_time = time.Now()
+	tm.flag.Set(trans_paused)
+}
+
+// Resumes a paused transfer, moving its start forward by the time paused.
+func (tm *tmon) Resume() {
+	tm.rate_lock.Lock()
+	defer tm.rate_lock.Unlock()
+	if !tm.flag.Has(trans_paused) {
+		return
+	}
+	now := time.Now()
+	tm.start_time = tm.start_time.Add(now.Sub(tm.pause_time))
+	tm.sample_time = now
+	tm.sample_size = atomic.LoadInt64(&tm.transferred)
+	tm.flag.Unset(trans_paused)
+}
+
+// Cancels the transfer, Close still needs to be called to close the source.
+func (tm *tmon) Cancel() {
+	if tm.flag.Has(trans_closed) {
+		return
+	}
+	tm.flag.Set(trans_cancelled | trans_closed)
+	tm.flag.Unset(trans_active)
+	if tm.flag.Has(NoSummary) {
+		return
+	}
+
+	transferred := atomic.LoadInt64(&tm.transferred)
+	elapsed := fmtDuration(tm.elapsed())
+	if total := tm.size(); total > 0 {
+		Warn("%s%s: cancelled at %d%% (%s/%s) after %s", tm.prefix, tm.name, int(float64(transferred)/float64(total)*100), tm.humanize(transferred), tm.humanize(total), elapsed)
+	} else {
+		Warn("%s%s: cancelled (%s) after %s", tm.prefix, tm.name, tm.humanize(transferred), elapsed)
+	}
+}
+
+// Returns time spent on the transfer, excluding time paused.
+func (t *tmon) elapsed() time.Duration {
+	t.rate_lock.Lock()
+	defer t.rate_lock.Unlock()
+	if t.flag.Has(trans_paused) {
+		return t.pause_time.Sub(t.start_time)
+	}
+	return time.Since(t.start_time)
+}
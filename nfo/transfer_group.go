@@ -0,0 +1,64 @@
+package nfo
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+)
+
+// Aggregate of multiple transfers, shown as one overall progress bar with each active transfer listed under it.
+type TransferGroup struct {
+	tm *tmon
+}
+
+// Creates a TransferGroup, transfers added with Add are summed into the group's transferred and total size.
+// The group's total size is unknown until a transfer of known size is added, and stays unknown once any of unknown size is added.
+// flag and optional_prefix are as TransferMonitor, ie.. NewTransferGroup("Uploading", LeftToRight)
+func NewTransferGroup(name string, flag int, optional_prefix ...string) *TransferGroup {
+	tm := newTmon(name, 0, flag, b_closer{bytes.NewReader(nil)}, optional_prefix...)
+	tm.flag.Set(trans_group)
+	displayTransfer(tm)
+	return &TransferGroup{tm}
+}
+
+// Adds a transfer to the group, parameters are as TransferMonitor.
+func (G *TransferGroup) Add(name string, total_size int64, flag int, source ReadSeekCloser) Transfer {
+	tm := newTmon(name, total_size, flag, source, G.tm.prefix)
+	tm.parent = G.tm
+	if total_size > 0 {
+		atomic.AddInt64(&G.tm.total_size, total_size)
+	} else if total_size < 0 {
+		G.tm.flag.Set(trans_unsized)
+	}
+	G.tm.flag.Unset(trans_complete)
+	displayTransfer(tm)
+	return tm
+}
+
+// Closes the group, logging a summary of all transfers added.
+func (G *TransferGroup) Close() error {
+	return G.tm.Close()
+}
+
+// Moves transferred by delta without counting it toward the rate, ie.. when a transfer of the group seeks.
+func (t *tmon) shift(delta int64) {
+	t.rate_lock.Lock()
+	defer t.rate_lock.Unlock()
+	atomic.AddInt64(&t.transferred, delta)
+	t.offset += delta
+	t.sample_size += delta
+}
+
+// Adds a write side transfer to the group, parameters are as TransferMonitorWriter.
+func (G *TransferGroup) AddWriter(name string, total_size int64, flag int, dest io.WriteCloser) TransferWriter {
+	W := newTmonWriter(name, total_size, flag, dest, G.tm.prefix)
+	W.parent = G.tm
+	if total_size > 0 {
+		atomic.AddInt64(&G.tm.total_size, total_size)
+	} else if total_size < 0 {
+		G.tm.flag.Set(trans_unsized)
+	}
+	G.tm.flag.Unset(trans_complete)
+	displayTransfer(W.tmon)
+	return W
+}
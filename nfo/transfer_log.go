@@ -0,0 +1,45 @@
+package nfo
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var progress_interval = int64(30 * time.Second)
+
+// Sets how often progress of transfers is logged as INFO when stderr is not a terminal, ie.. in CI logs, 0 to disable. (Default: 30s)
+func SetProgressInterval(interval time.Duration) {
+	atomic.StoreInt64(&progress_interval, int64(interval))
+}
+
+// Logs progress of monitors, oldest first, once the progress interval has passed since last.
+func logProgress(monitors []*tmon, last *time.Time) {
+	interval := time.Duration(atomic.LoadInt64(&progress_interval))
+	if interval <= 0 || time.Since(*last) < interval {
+		return
+	}
+	*last = time.Now()
+
+	for i := len(monitors) - 1; i >= 0; i-- {
+		v := monitors[i]
+		if v.flag.Has(trans_active) && !v.flag.Has(trans_paused) && !v.flag.Has(internal) {
+			Log(v.progressLine())
+		}
+	}
+}
+
+// Outputs progress of the transfer as a single line, for logs.
+func (t *tmon) progressLine() string {
+	transferred := atomic.LoadInt64(&t.transferred)
+	rate := t.showRate()
+
+	if total := t.size(); total > -1 {
+		num := 100
+		if total > 0 {
+			num = int(float64(transferred) / float64(total) * 100)
+		}
+		return fmt.Sprintf("%s%s: %d%% %s (%s/%s) %s", t.prefix, t.name, num, rate, t.humanize(transferred), t.humanize(total), t.showTime())
+	}
+	return fmt.Sprintf("%s%s: %s (%s) %s", t.prefix, t.name, rate, t.humanize(transferred), t.showTime())
+}
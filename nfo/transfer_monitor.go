@@ -2,12 +2,11 @@ package nfo
 
 import (
 	"fmt"
-	. "github.com/cmcoffee/snugforge/xsync"
-	"golang.org/x/crypto/ssh/terminal"
+	"github.com/cmcoffee/snugforge/xsync"
 	"io"
+	"strings"
 	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 )
 
@@ -39,7 +38,7 @@ func NopSeeker(input io.ReadCloser) ReadSeekCloser {
 }
 
 func termWidth() int {
-	width, _, _ := terminal.GetSize(int(syscall.Stderr))
+	width := int(atomic.LoadInt64(&term_cols))
 	width--
 	if width < 1 {
 		width = 0
@@ -47,6 +46,10 @@ func termWidth() int {
 	return width
 }
 
+func termHeight() int {
+	return int(atomic.LoadInt64(&term_rows))
+}
+
 const (
 	LeftToRight        = 1 << iota // Display progress bar left to right. (Default Behavior)
 	RightToLeft                    // Display progress bar right to left.
@@ -59,6 +62,10 @@ const (
 	trans_closed
 	trans_complete
 	trans_error
+	trans_group
+	trans_paused
+	trans_cancelled
+	trans_unsized
 )
 
 type readSeekCounter struct {
@@ -82,12 +89,16 @@ func TransferCounter(input ReadSeekCloser, counter func(int)) ReadSeekCloser {
 
 // Add Transfer to transferDisplay.
 // Parameters are "name" displayed for file transfer, "limit_sz" for when to pause transfer (aka between calls/chunks), and "total_sz" the total size of the transfer.
+// The returned ReadSeekCloser is a Transfer, ie.. TransferMonitor(name, size, 0, f).(Transfer).Pause()
 func TransferMonitor(name string, total_size int64, flag int, source ReadSeekCloser, optional_prefix ...string) ReadSeekCloser {
-	transferDisplay.update_lock.Lock()
-	defer transferDisplay.update_lock.Unlock()
+	tm := newTmon(name, total_size, flag, source, optional_prefix...)
+	displayTransfer(tm)
+	return tm
+}
 
+// Creates a transfer monitor, to be shown with displayTransfer.
+func newTmon(name string, total_size int64, flag int, source ReadSeekCloser, optional_prefix ...string) *tmon {
 	var (
-		short_name  []rune
 		target_size int
 		prefix      string
 	)
@@ -96,7 +107,7 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 		prefix = optional_prefix[0]
 	}
 
-	b_flag := BitFlag(flag)
+	b_flag := xsync.BitFlag(flag)
 	if b_flag.Has(LeftToRight) || b_flag <= 0 {
 		b_flag.Set(LeftToRight)
 	}
@@ -111,22 +122,13 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 		target_size = 40
 	}
 
-	for i, v := range name {
-		if i < target_size {
-			short_name = append(short_name, v)
-		} else {
-			short_name = append(short_name, []rune("..")[0:]...)
-			break
-		}
+	short_name := name
+	if textWidth(name) > target_size {
+		short_name = truncateWidth(name, target_size) + ".."
 	}
 
-	if len(short_name) < target_size && (!b_flag.Has(internal) && !b_flag.Has(ProgressBarSummary)) {
-		x := len(short_name) - 1
-		var y []rune
-		for i := 0; i <= target_size-x; i++ {
-			y = append(y, ' ')
-		}
-		short_name = append(y[0:], short_name[0:]...)
+	if !b_flag.Has(internal) && !b_flag.Has(ProgressBarSummary) {
+		short_name = padWidth(short_name, target_size+2)
 	}
 
 	b_flag.Set(trans_active)
@@ -135,7 +137,7 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 		flag:        b_flag,
 		name:        name,
 		prefix:      prefix,
-		short_name:  string(short_name),
+		short_name:  short_name,
 		total_size:  total_size,
 		transferred: 0,
 		offset:      0,
@@ -143,6 +145,13 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 		start_time:  time.Now(),
 		source:      source,
 	}
+	return tm
+}
+
+// Adds tm to transferDisplay, starting the display if needed.
+func displayTransfer(tm *tmon) {
+	transferDisplay.update_lock.Lock()
+	defer transferDisplay.update_lock.Unlock()
 
 	var spin_index int
 	spin_txt := []string{"\\", "|", "/", "-"}
@@ -163,6 +172,7 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 		transferDisplay.display = 1
 
 		go func() {
+			last_log := time.Now()
 			for {
 				transferDisplay.update_lock.Lock()
 
@@ -185,69 +195,113 @@ func TransferMonitor(name string, total_size int64, flag int, source ReadSeekClo
 
 				transferDisplay.update_lock.Unlock()
 
-				// Display transfers.
-				for _, v := range monitors {
-					for i := 0; i < 10; i++ {
-						if v.flag.Has(trans_active) {
-							Flash("[%s] %s", spinner(), v.showTransfer(false))
-						} else {
-							break
+				// Log progress in place of display when stderr is not a terminal.
+				if piped_stderr {
+					logProgress(monitors, &last_log)
+					time.Sleep(time.Millisecond * 200)
+					continue
+				}
+
+				// Display transfers, one line each, oldest first, with transfers of a group listed under it.
+				var lines []string
+				spin := spinner()
+				for i := len(monitors) - 1; i >= 0; i-- {
+					v := monitors[i]
+					if !v.flag.Has(trans_active) || v.flag.Has(trans_paused) || v.parent != nil && !v.parent.flag.Has(trans_closed) {
+						continue
+					}
+					lines = append(lines, fmt.Sprintf("[%s] %s", spin, v.showTransfer(false)))
+					if !v.flag.Has(trans_group) {
+						continue
+					}
+					for j := len(monitors) - 1; j >= 0; j-- {
+						if monitors[j].parent == v && monitors[j].flag.Has(trans_active) && !monitors[j].flag.Has(trans_paused) {
+							lines = append(lines, fmt.Sprintf("    %s", monitors[j].showTransfer(false)))
 						}
-						time.Sleep(time.Millisecond * 200)
 					}
 				}
+
+				// Keep the display within the terminal, so lines can be repainted.
+				if rows := termHeight() - 1; rows > 0 && len(lines) > rows {
+					hidden := len(lines) - rows + 1
+					lines = append(lines[:rows-1], fmt.Sprintf("... and %d more transfers.", hidden))
+				}
+
+				if len(lines) > 0 {
+					Flash("%s", strings.Join(lines, "\n"))
+				}
+				time.Sleep(time.Millisecond * 200)
 			}
 		}()
 
 	}
-
-	return tm
 }
 
 // Wrapper Seeker
 func (tm *tmon) Seek(offset int64, whence int) (int64, error) {
 	o, err := tm.source.Seek(offset, whence)
-	tm.transferred = o
+	if tm.parent != nil {
+		tm.parent.shift(o - atomic.LoadInt64(&tm.transferred))
+	}
+	atomic.StoreInt64(&tm.transferred, o)
 	tm.offset = o
+	tm.rate_lock.Lock()
+	tm.sample_time = time.Now()
+	tm.sample_size = o
+	tm.rate_lock.Unlock()
 	return o, err
 }
 
 // Wrapped Reader
 func (tm *tmon) Read(p []byte) (n int, err error) {
 	n, err = tm.source.Read(p)
-	atomic.StoreInt64(&tm.transferred, atomic.LoadInt64(&tm.transferred)+int64(n))
+	tm.count(n)
 	if err != nil {
 		if tm.flag.Has(trans_closed) {
 			return
 		}
 		tm.flag.Set(trans_closed | trans_error)
-		if tm.transferred == 0 {
+		if atomic.LoadInt64(&tm.transferred) == 0 {
 			return
 		}
 	}
 	return
 }
 
+// Adds n bytes to the transfer, and its group.
+func (tm *tmon) count(n int) {
+	if tm.flag.Has(trans_paused) {
+		tm.shift(int64(n))
+		if tm.parent != nil {
+			tm.parent.shift(int64(n))
+		}
+	} else {
+		atomic.StoreInt64(&tm.transferred, atomic.LoadInt64(&tm.transferred)+int64(n))
+		if tm.parent != nil {
+			atomic.AddInt64(&tm.parent.transferred, int64(n))
+		}
+	}
+}
+
 // Close out speicfic transfer monitor
 func (tm *tmon) Close() error {
+	if tm.flag.Has(trans_cancelled) {
+		return tm.source.Close()
+	}
 	tm.flag.Set(trans_closed)
-	if (tm.transferred > 0 || tm.total_size == 0) && !tm.flag.Has(NoSummary) {
+	if (atomic.LoadInt64(&tm.transferred) > 0 || tm.size() == 0) && !tm.flag.Has(NoSummary) {
 		Log(tm.showTransfer(true))
 	}
 	return tm.source.Close()
 }
 
 func spacePrint(min int, input string) string {
-	output := make([]rune, min)
-	for i := 0; i < len(output); i++ {
-		output[i] = ' '
-	}
-	return string(append(output[len(input)-1:], []rune(input)[0:]...))
+	return padWidth(input, min+1)
 }
 
 // Transfer Monitor
 type tmon struct {
-	flag        BitFlag
+	flag        xsync.BitFlag
 	prefix      string
 	name        string
 	short_name  string
@@ -258,6 +312,105 @@ type tmon struct {
 	chunk_size  int64
 	start_time  time.Time
 	source      ReadSeekCloser
+	parent      *tmon // Group of the transfer.
+	units       Units // Humanizer of counts, HumanSize when nil.
+	rate_lock   sync.Mutex
+	bps         float64   // Smoothed bytes per second.
+	sampled     bool      // bps holds a sample.
+	sample_time time.Time // Time of last sample.
+	sample_size int64     // Bytes transferred at last sample.
+	pause_time  time.Time // Time transfer was paused.
+}
+
+const (
+	rate_sample = 500 * time.Millisecond // Minimum time between rate samples.
+	rate_window = 5 * time.Second        // Time over which rate samples are smoothed.
+)
+
+// Formats d as mm:ss, or h:mm:ss.
+func fmtDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// Returns the bytes per second of the transfer, smoothed over rate_window while active, or the average once closed.
+func (t *tmon) bytesPerSec() float64 {
+	t.rate_lock.Lock()
+	defer t.rate_lock.Unlock()
+
+	transferred := atomic.LoadInt64(&t.transferred)
+	now := time.Now()
+	if t.flag.Has(trans_paused) {
+		now = t.pause_time
+	}
+
+	since := now.Sub(t.start_time).Seconds()
+	if since < 0.1 {
+		since = 0.1
+	}
+	average := float64(transferred-t.offset) / since
+
+	if t.flag.Has(trans_closed) {
+		t.bps = average
+		return t.bps
+	}
+
+	if t.sample_time.IsZero() {
+		t.sample_time = t.start_time
+		t.sample_size = t.offset
+	}
+
+	if elapsed := now.Sub(t.sample_time); elapsed >= rate_sample {
+		current := float64(transferred-t.sample_size) / elapsed.Seconds()
+		if !t.sampled {
+			t.bps = current
+			t.sampled = true
+		} else {
+			weight := elapsed.Seconds() / rate_window.Seconds()
+			if weight > 1 {
+				weight = 1
+			}
+			t.bps += weight * (current - t.bps)
+		}
+		t.sample_time = now
+		t.sample_size = transferred
+	}
+
+	if !t.sampled {
+		return average
+	}
+	return t.bps
+}
+
+// Returns time elapsed, and while active, estimated time remaining.
+func (t *tmon) showTime() string {
+	elapsed := fmtDuration(t.elapsed())
+	if !t.flag.Has(trans_active) {
+		return fmt.Sprintf("in %s", elapsed)
+	}
+	total := t.size()
+	if total <= 0 {
+		return elapsed
+	}
+
+	t.rate_lock.Lock()
+	bps := t.bps
+	t.rate_lock.Unlock()
+
+	remaining := total - atomic.LoadInt64(&t.transferred)
+	if remaining < 0 {
+		remaining = 0
+	}
+	if bps <= 0 {
+		return fmt.Sprintf("%s eta --:--", elapsed)
+	}
+	return fmt.Sprintf("%s eta %s", elapsed, fmtDuration(time.Duration(float64(remaining)/bps*float64(time.Second))))
 }
 
 // Outputs progress of TMonitor.
@@ -275,27 +428,22 @@ func (t *tmon) showTransfer(summary bool) string {
 	}
 
 	// 35 + 8 +8 + 8 + 8
-	if t.total_size > -1 {
+	if t.size() > -1 {
 		return fmt.Sprintf("%s", t.progressBar(name))
 	} else {
-		return fmt.Sprintf("%s: %s (%s) ", t.name, rate, HumanSize(transferred))
+		return fmt.Sprintf("%s: %s (%s) %s ", t.name, rate, t.humanize(transferred), t.showTime())
 	}
 }
 
-// Provides average rate of transfer.
+// Provides smoothed rate of transfer, or the average rate once closed.
 func (t *tmon) showRate() (rate string) {
 
 	transferred := atomic.LoadInt64(&t.transferred)
-	if transferred == 0 || t.flag.Has(trans_complete) {
+	if transferred == 0 || t.flag.Has(trans_complete) && !t.flag.Has(trans_closed) {
 		return t.rate
 	}
 
-	since := time.Since(t.start_time).Seconds()
-	if since < 0.1 {
-		since = 0.1
-	}
-
-	sz := float64(transferred-t.offset) * 8 / since
+	sz := t.bytesPerSec() * 8
 
 	names := []string{
 		"bps",
@@ -323,18 +471,28 @@ func (t *tmon) showRate() (rate string) {
 
 	t.rate = rate
 
-	if !t.flag.Has(trans_complete) && atomic.LoadInt64(&t.transferred)+t.offset == t.total_size {
+	if !t.flag.Has(trans_complete) && atomic.LoadInt64(&t.transferred)+t.offset == t.size() {
 		t.flag.Set(trans_complete)
 	}
 
 	return t.rate
 }
 
+// Returns the total size of the transfer, or -1 if unknown, ie.. a group without transfers of known size, or with any of unknown size.
+func (t *tmon) size() int64 {
+	total := atomic.LoadInt64(&t.total_size)
+	if t.flag.Has(trans_group) && (total == 0 || t.flag.Has(trans_unsized)) {
+		return -1
+	}
+	return total
+}
+
 // Produces progress bar for information on update.
 func (t *tmon) progressBar(name string) string {
-	num := int((float64(atomic.LoadInt64(&t.transferred)) / float64(t.total_size)) * 100)
+	total := t.size()
+	num := int((float64(atomic.LoadInt64(&t.transferred)) / float64(total)) * 100)
 
-	if t.total_size == 0 {
+	if total == 0 {
 		num = 100
 	}
 
@@ -347,12 +505,15 @@ func (t *tmon) progressBar(name string) string {
 
 	if !t.flag.Has(NoRate) {
 		first_half = fmt.Sprintf("%s: %s", name, t.showRate())
-		second_half = fmt.Sprintf("(%s/%s)", HumanSize(t.transferred), HumanSize(t.total_size))
+		second_half = fmt.Sprintf("(%s/%s) %s", t.humanize(atomic.LoadInt64(&t.transferred)), t.humanize(total), t.showTime())
 	} else {
 		first_half = fmt.Sprintf("%s:", name)
+		if t.units != nil {
+			second_half = fmt.Sprintf("(%s/%s)", t.humanize(atomic.LoadInt64(&t.transferred)), t.humanize(total))
+		}
 	}
 
-	sz = sz - len(first_half) - len(second_half) - 15
+	sz = sz - textWidth(first_half) - textWidth(second_half) - 15
 
 	if t.flag.Has(trans_closed) && !t.flag.Has(ProgressBarSummary) && !t.flag.Has(NoSummary) || sz <= 0 {
 		sz = 10
@@ -0,0 +1,58 @@
+package nfo
+
+import (
+	"io"
+)
+
+// Transfer returned by TransferMonitorWriter.
+type TransferWriter interface {
+	io.WriteCloser
+	Pause()  // Hides the transfer from display, time and bytes while paused do not count toward its rate.
+	Resume() // Shows a paused transfer again.
+	Cancel() // Marks the transfer aborted, logging a cancelled summary in place of the completed one.
+}
+
+// Write side transfer monitor.
+type tmonWriter struct {
+	*tmon
+	dest io.WriteCloser
+}
+
+// Closer of dest, as source of the transfer monitor.
+type destCloser struct {
+	io.Closer
+}
+
+func (d destCloser) Read(p []byte) (n int, err error) {
+	return 0, io.EOF
+}
+
+func (d destCloser) Seek(offset int64, whence int) (int64, error) {
+	return 0, nil
+}
+
+// Add write side Transfer to transferDisplay, ie.. a download written to disk.
+// Parameters are as TransferMonitor, with bytes counted as they are written to dest.
+func TransferMonitorWriter(name string, total_size int64, flag int, dest io.WriteCloser, optional_prefix ...string) TransferWriter {
+	W := newTmonWriter(name, total_size, flag, dest, optional_prefix...)
+	displayTransfer(W.tmon)
+	return W
+}
+
+// Creates a write side transfer monitor, to be shown with displayTransfer.
+func newTmonWriter(name string, total_size int64, flag int, dest io.WriteCloser, optional_prefix ...string) *tmonWriter {
+	return &tmonWriter{
+		tmon: newTmon(name, total_size, flag, destCloser{dest}, optional_prefix...),
+		dest: dest,
+	}
+}
+
+// Wrapped Writer
+func (W *tmonWriter) Write(p []byte) (n int, err error) {
+	n, err = W.dest.Write(p)
+	W.count(n)
+	if err != nil && !W.flag.Has(trans_closed) {
+		W.flag.Set(trans_closed | trans_error)
+	}
+	return
+}
@@ -0,0 +1,23 @@
+package nfo
+
+import (
+	"fmt"
+)
+
+// Humanizer of counts shown by a progress bar, see NewProgressBar.
+type Units func(count int64) string
+
+// Units of named items, ie.. Items("files") shows 42 files.
+func Items(name string) Units {
+	return func(count int64) string {
+		return fmt.Sprintf("%d %s", count, name)
+	}
+}
+
+// Returns count in the units of the transfer.
+func (t *tmon) humanize(count int64) string {
+	if t.units == nil {
+		return HumanSize(count)
+	}
+	return t.units(count)
+}
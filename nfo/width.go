@@ -0,0 +1,72 @@
+package nfo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// East Asian wide and fullwidth ranges, which take two columns on a terminal.
+var wide_runes = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x231A, 0x231B},   // Watch, hourglass
+	{0x2329, 0x232A},   // Angle brackets
+	{0x2E80, 0x303E},   // CJK radicals, symbols and punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xA960, 0xA97F},   // Hangul Jamo extended A
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE10, 0xFE19},   // Vertical forms
+	{0xFE30, 0xFE6F},   // CJK compatibility forms, small forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1F64F}, // Pictographs, emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental pictographs
+	{0x20000, 0x2FFFD}, // CJK extension B and beyond
+	{0x30000, 0x3FFFD}, // CJK extension G and beyond
+}
+
+// Returns the number of terminal columns used by r.
+func runeWidth(r rune) int {
+	if r < 0x20 || r == 0x7F || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	if r < wide_runes[0][0] {
+		return 1
+	}
+	for _, v := range wide_runes {
+		if r >= v[0] && r <= v[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Returns the number of terminal columns used by input.
+func textWidth(input string) (width int) {
+	for _, r := range input {
+		width += runeWidth(r)
+	}
+	return
+}
+
+// Cuts input down to width columns, without splitting runes.
+func truncateWidth(input string, width int) string {
+	var used int
+	for i, r := range input {
+		if used += runeWidth(r); used > width {
+			return input[:i]
+		}
+	}
+	return input
+}
+
+// Pads input on the left with spaces to fill width columns.
+func padWidth(input string, width int) string {
+	if n := width - textWidth(input); n > 0 {
+		return strings.Repeat(" ", n) + input
+	}
+	return input
+}
@@ -0,0 +1,41 @@
+package nfo
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Line buffered writer which logs each line.
+type lineWriter struct {
+	level  uint32
+	prefix string
+	buffer []byte
+	mutex  sync.Mutex
+}
+
+// Returns a writer which logs each line written to it as level with prefix, ie.. log.New(nfo.NewWriter(nfo.ERROR, "http: "), "", 0)
+// Partial lines are held until completed by a newline.
+func NewWriter(level uint32, prefix string) io.Writer {
+	return &lineWriter{level: level, prefix: prefix}
+}
+
+func (w *lineWriter) Write(p []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+		line := bytes.TrimRight(w.buffer[:i], "\r")
+		write2log(w.level, w.prefix+string(line))
+		w.buffer = w.buffer[i+1:]
+	}
+	if len(w.buffer) == 0 {
+		w.buffer = nil
+	}
+	return len(p), nil
+}
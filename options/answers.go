@@ -0,0 +1,77 @@
+package options
+
+import (
+	. "github.com/cmcoffee/snugforge/nfo"
+	"strconv"
+	"strings"
+)
+
+// Sets options from the answers given to nfo.SetNonInteractive, keyed by desc, nested Options are answered in turn.
+func (T *Options) answer() (changed bool) {
+	for _, v := range T.config {
+		switch o := v.(type) {
+		case *stringValue:
+			if answer, ok := Answer(o.desc); ok && answer != "" {
+				*o.value = answer
+				changed = true
+			}
+		case *boolValue:
+			answer, ok := Answer(o.desc)
+			if !ok {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes", "true":
+				*o.value = true
+			case "n", "no", "false":
+				*o.value = false
+			default:
+				Fatal("Cannot set \"%s\" non-interactively: %q is not yes or no", o.desc, answer)
+			}
+			changed = true
+		case *intValue:
+			answer, ok := Answer(o.desc)
+			if !ok {
+				continue
+			}
+			val, err := strconv.Atoi(strings.TrimSpace(answer))
+			if err != nil || val < o.min || val > o.max {
+				Fatal("Cannot set \"%s\" non-interactively: %q is not an integer between %d and %d", o.desc, answer, o.min, o.max)
+			}
+			*o.value = val
+			changed = true
+		case *choiceValue:
+			answer, ok := Answer(o.desc)
+			if !ok {
+				continue
+			}
+			choice, ok := matchChoice(o.choices, answer)
+			if !ok {
+				Fatal("Cannot set \"%s\" non-interactively: %q is not one of %s", o.desc, answer, strings.Join(o.choices, ", "))
+			}
+			*o.value = choice
+			changed = true
+		case *pathValue:
+			answer, ok := Answer(o.desc)
+			if !ok || answer == "" {
+				continue
+			}
+			path := expandPath(answer)
+			if err := o.check(path); err != nil {
+				Fatal("Cannot set \"%s\" non-interactively: %s", o.desc, err)
+			}
+			*o.value = path
+			changed = true
+		case *stringsValue:
+			if answer, ok := Answer(o.desc); ok {
+				*o.value = splitList(answer)
+				changed = true
+			}
+		case *optionsValue:
+			if o.value.answer() {
+				changed = true
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,94 @@
+package options
+
+import (
+	"github.com/cmcoffee/snugforge/kvlite"
+	. "github.com/cmcoffee/snugforge/nfo"
+)
+
+// Binds the Options menu to store, values are loaded from store now and saved to it when Select exits.
+// Values are keyed by desc, nested Options without a store of their own are kept under "<desc>/", secrets are encrypted.
+// Bind should be called after all values are registered.
+func (T *Options) Bind(store kvlite.Table) (err error) {
+	T.store = store
+	return T.load(store, "")
+}
+
+// Loads registered values from store, keyed under prefix.
+func (T *Options) load(store kvlite.Table, prefix string) (err error) {
+	for _, v := range T.config {
+		switch o := v.(type) {
+		case *stringValue:
+			_, err = store.Get(prefix+o.desc, o.value)
+		case *boolValue:
+			_, err = store.Get(prefix+o.desc, o.value)
+		case *pathValue:
+			_, err = store.Get(prefix+o.desc, o.value)
+		case *stringsValue:
+			_, err = store.Get(prefix+o.desc, o.value)
+		case *intValue:
+			var val int
+			var found bool
+			found, err = store.Get(prefix+o.desc, &val)
+			if found && val >= o.min && val <= o.max {
+				*o.value = val
+			}
+		case *choiceValue:
+			var val string
+			var found bool
+			found, err = store.Get(prefix+o.desc, &val)
+			if choice, ok := matchChoice(o.choices, val); found && ok {
+				*o.value = choice
+			}
+		case *optionsValue:
+			if o.value.store == nil {
+				err = o.value.load(store, prefix+o.desc+"/")
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Saves registered values to store, keyed under prefix.
+func (T *Options) save(store kvlite.Table, prefix string) (err error) {
+	for _, v := range T.config {
+		switch o := v.(type) {
+		case *stringValue:
+			if o.mask {
+				err = store.CryptSet(prefix+o.desc, *o.value)
+			} else {
+				err = store.Set(prefix+o.desc, *o.value)
+			}
+		case *boolValue:
+			err = store.Set(prefix+o.desc, *o.value)
+		case *intValue:
+			err = store.Set(prefix+o.desc, *o.value)
+		case *choiceValue:
+			err = store.Set(prefix+o.desc, *o.value)
+		case *pathValue:
+			err = store.Set(prefix+o.desc, *o.value)
+		case *stringsValue:
+			err = store.Set(prefix+o.desc, *o.value)
+		case *optionsValue:
+			if o.value.store == nil {
+				err = o.value.save(store, prefix+o.desc+"/")
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Saves values to the bound store, if any, reporting failures.
+func (T *Options) commit() {
+	if T.store == nil {
+		return
+	}
+	if err := T.save(T.store, ""); err != nil {
+		Err("Unable to save options: %s", err)
+	}
+}
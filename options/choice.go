@@ -0,0 +1,83 @@
+package options
+
+import (
+	"fmt"
+	. "github.com/cmcoffee/snugforge/nfo"
+	"strconv"
+	"strings"
+)
+
+// Choice defines a menu option limited to one of choices, selecting it toggles between two choices or prompts among more, displaying with specified desc in menu and default value def. The return value is the address of a string variable that stores the value of the option.
+func (O *Options) Choice(desc string, choices []string, def string) *string {
+	value, ok := matchChoice(choices, def)
+	if !ok {
+		value = def
+		if len(choices) > 0 {
+			value = choices[0]
+		}
+	}
+	O.Register(&choiceValue{
+		desc:    desc,
+		value:   &value,
+		choices: choices,
+	})
+	return &value
+}
+
+// Choice value.
+type choiceValue struct {
+	desc    string
+	value   *string
+	choices []string
+}
+
+// Returns the choice matching input, by name or by number.
+func matchChoice(choices []string, input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	for _, v := range choices {
+		if strings.EqualFold(v, input) {
+			return v, true
+		}
+	}
+	if n, err := strconv.Atoi(input); err == nil && n > 0 && n <= len(choices) {
+		return choices[n-1], true
+	}
+	return "", false
+}
+
+// Two choices are toggled like Bool, more are prompted for.
+func (C *choiceValue) Set() bool {
+	if len(C.choices) == 2 {
+		if strings.EqualFold(*C.value, C.choices[0]) {
+			*C.value = C.choices[1]
+		} else {
+			*C.value = C.choices[0]
+		}
+		return true
+	}
+	for {
+		var list []string
+		for i, v := range C.choices {
+			list = append(list, fmt.Sprintf("[%d] %s", i+1, v))
+		}
+		input := GetInput(fmt.Sprintf("\n# %s\n--> %s: ", strings.Join(list, " "), C.desc))
+		if len(input) == 0 {
+			return false
+		}
+		choice, ok := matchChoice(C.choices, input)
+		if !ok {
+			Stdout("\n[ERROR] Value must be one of: %s.", strings.Join(C.choices, ", "))
+			continue
+		}
+		*C.value = choice
+		return true
+	}
+}
+
+func (C *choiceValue) Get() interface{} {
+	return C.value
+}
+
+func (C *choiceValue) String() string {
+	return fmt.Sprintf("%s:\t%s", C.desc, *C.value)
+}
@@ -0,0 +1,64 @@
+package options
+
+import (
+	"fmt"
+)
+
+// Info defines a read-only line displaying with specified desc in menu, value is called each time the menu is shown, ie.. for a version or computed status.
+func (O *Options) Info(desc string, value func() string) {
+	O.Register(&infoValue{
+		desc:  desc,
+		value: value,
+	})
+}
+
+// Separator defines a break between menu options, showing title when set, or a blank line otherwise.
+func (O *Options) Separator(title string) {
+	O.Register(&separatorValue{
+		title: title,
+	})
+}
+
+// Returns false for values shown in the menu that can't be selected.
+func selectable(v Value) bool {
+	switch v.(type) {
+	case *infoValue, *separatorValue:
+		return false
+	}
+	return true
+}
+
+// Read-only line.
+type infoValue struct {
+	desc  string
+	value func() string
+}
+
+func (I *infoValue) Set() bool {
+	return false
+}
+
+func (I *infoValue) Get() interface{} {
+	return nil
+}
+
+func (I *infoValue) String() string {
+	return fmt.Sprintf("%s:\t%s", I.desc, I.value())
+}
+
+// Menu separator.
+type separatorValue struct {
+	title string
+}
+
+func (S *separatorValue) Set() bool {
+	return false
+}
+
+func (S *separatorValue) Get() interface{} {
+	return nil
+}
+
+func (S *separatorValue) String() string {
+	return S.title
+}
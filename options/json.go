@@ -0,0 +1,220 @@
+package options
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrBadKey      = errors.New("Unable to decrypt secret, key is incorrect.")
+	ErrKeyRequired = errors.New("Secret is encrypted, a key is required.")
+)
+
+// Secret as written to a JSON file when encrypted.
+type encryptedValue struct {
+	Encrypted string `json:"encrypted"`
+}
+
+// Saves all registered values to path as JSON, keyed by desc, nested Options are saved as nested objects.
+// Secrets are encrypted with key, or saved as plain text when key is empty.
+func (T *Options) SaveJSON(path string, key []byte) error {
+	values, err := T.jsonValues(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Loads registered values from a JSON file written by SaveJSON, key must match the key the file was saved with.
+// Values in the file that aren't registered are ignored, as are registered values missing from the file.
+func (T *Options) LoadJSON(path string, key []byte) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	if err := T.loadJSON(values, key); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	return nil
+}
+
+// Returns registered values as a map for encoding.
+func (T *Options) jsonValues(key []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, v := range T.config {
+		switch o := v.(type) {
+		case *stringValue:
+			if o.mask && len(key) > 0 {
+				encrypted, err := encryptSecret(key, *o.value)
+				if err != nil {
+					return nil, err
+				}
+				values[o.desc] = encryptedValue{encrypted}
+			} else {
+				values[o.desc] = *o.value
+			}
+		case *boolValue:
+			values[o.desc] = *o.value
+		case *intValue:
+			values[o.desc] = *o.value
+		case *choiceValue:
+			values[o.desc] = *o.value
+		case *pathValue:
+			values[o.desc] = *o.value
+		case *stringsValue:
+			values[o.desc] = *o.value
+		case *optionsValue:
+			nested, err := o.value.jsonValues(key)
+			if err != nil {
+				return nil, err
+			}
+			values[o.desc] = nested
+		}
+	}
+	return values, nil
+}
+
+// Sets registered values from decoded JSON.
+func (T *Options) loadJSON(values map[string]json.RawMessage, key []byte) (err error) {
+	for _, v := range T.config {
+		var desc string
+		switch o := v.(type) {
+		case *stringValue:
+			desc = o.desc
+			err = o.loadJSON(values[desc], key)
+		case *boolValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				err = json.Unmarshal(raw, o.value)
+			}
+		case *pathValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				var val string
+				if err = json.Unmarshal(raw, &val); err == nil {
+					*o.value = expandPath(val)
+				}
+			}
+		case *stringsValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				err = json.Unmarshal(raw, o.value)
+			}
+		case *intValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				var val int
+				if err = json.Unmarshal(raw, &val); err == nil {
+					if val > o.max || val < o.min {
+						err = fmt.Errorf("%d is outside of acceptable range of %d and %d.", val, o.min, o.max)
+					} else {
+						*o.value = val
+					}
+				}
+			}
+		case *choiceValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				var val string
+				if err = json.Unmarshal(raw, &val); err == nil {
+					if choice, ok := matchChoice(o.choices, val); ok {
+						*o.value = choice
+					} else {
+						err = fmt.Errorf("%q is not one of %s.", val, strings.Join(o.choices, ", "))
+					}
+				}
+			}
+		case *optionsValue:
+			desc = o.desc
+			if raw, ok := values[desc]; ok {
+				var nested map[string]json.RawMessage
+				if err = json.Unmarshal(raw, &nested); err == nil {
+					err = o.value.loadJSON(nested, key)
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", desc, err)
+		}
+	}
+	return nil
+}
+
+// Sets the string from raw JSON, decrypting it with key if encrypted.
+func (S *stringValue) loadJSON(raw json.RawMessage, key []byte) error {
+	if raw == nil {
+		return nil
+	}
+	var encrypted encryptedValue
+	if !S.mask || json.Unmarshal(raw, &encrypted) != nil {
+		return json.Unmarshal(raw, S.value)
+	}
+	if len(key) == 0 {
+		return ErrKeyRequired
+	}
+	secret, err := decryptSecret(key, encrypted.Encrypted)
+	if err != nil {
+		return err
+	}
+	*S.value = secret
+	return nil
+}
+
+// Returns a cipher for secrets from key.
+func secretCipher(key []byte) (cipher.AEAD, error) {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypts secret with key, returning it base64 encoded.
+func encryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// Decrypts a secret encrypted by encryptSecret.
+func decryptSecret(key []byte, encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrBadKey
+	}
+	gcm, err := secretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", ErrBadKey
+	}
+	secret, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrBadKey
+	}
+	return string(secret), nil
+}
@@ -3,6 +3,7 @@ package options
 import (
 	"bytes"
 	"fmt"
+	"github.com/cmcoffee/snugforge/kvlite"
 	. "github.com/cmcoffee/snugforge/nfo"
 	"github.com/cmcoffee/snugforge/xsync"
 	"strconv"
@@ -16,8 +17,14 @@ type Options struct {
 	exit_char rune
 	flags     xsync.BitFlag
 	config    []Value
+	store     kvlite.Table
+	required  map[string]bool
+	page_size int
 }
 
+// Number of lines shown per page when no page size is set, see PageSize.
+const default_page_size = 20
+
 // Options Value
 type Value interface {
 	Set() bool
@@ -36,13 +43,31 @@ func NewOptions(header, footer string, exit_char rune) *Options {
 	}
 }
 
+// Sets the number of lines shown per page of the menu, menus longer than this are paged and can be searched with /<text>.
+func (T *Options) PageSize(lines int) {
+	T.page_size = lines
+}
+
 // Registers an Value with Options Menu
 func (T *Options) Register(input Value) {
 	T.config = append(T.config, input)
 }
 
 // Show Options Menu, if separate_last = true, the last menu item will be dropped one line, and it's select number will be 0, seperating it from the rest.
+// When prompts are resolved non-interactively, options are set from answers by their desc instead, see nfo.SetNonInteractive.
+// Values are saved on exit when bound to a store, see Bind, exit is refused while required options are unconfigured, see Required.
+// Long menus are paged, see PageSize, and /<text> jumps to the option with a matching desc, or lists them when several match.
 func (T *Options) Select(separate_last bool) (changed bool) {
+	defer T.commit()
+
+	if NonInteractive() {
+		changed = T.answer()
+		if missing := T.missing(); len(missing) > 0 {
+			Fatal("Required options are unconfigured: %s", strings.Join(missing, ", "))
+		}
+		return
+	}
+
 	var text_buffer bytes.Buffer
 	txt := tabwriter.NewWriter(&text_buffer, 1, 8, 1, ' ', 0)
 
@@ -59,27 +84,129 @@ func (T *Options) Select(separate_last bool) (changed bool) {
 
 	show_banner()
 
+	// Sets the selected option.
+	choose := func(v Value) {
+		changed = v.Set()
+		switch v.(type) {
+		case *funcValue:
+			Stdout("\n")
+			show_banner()
+		case *optionsValue:
+			Stdout("\n")
+			show_banner()
+		default:
+			Stdout("\n")
+		}
+	}
+
+	var (
+		page   int
+		filter string
+	)
+
 	for {
 		text_buffer.Reset()
 		config_map := make(map[int]Value)
 		config_len := len(T.config) - 1
 
+		var (
+			num   int
+			lines []string
+			last  string
+		)
+
 		for i := 0; i <= config_len; i++ {
-			if i == config_len && config_len > 0 && separate_last {
+			if i == config_len && config_len > 0 && separate_last && selectable(T.config[i]) {
 				config_map[0] = T.config[config_len]
-				fmt.Fprintf(txt, "\t\n")
-				fmt.Fprintf(txt, " [0] %s\n", T.config[config_len].String())
+				last = fmt.Sprintf("\t\n [0] %s\n", T.config[config_len].String())
 				break
 			}
-			config_map[i+1] = T.config[i]
-			fmt.Fprintf(txt, " [%d] %s\n", i+1, T.config[i].String())
+			switch v := T.config[i].(type) {
+			case *separatorValue:
+				if len(filter) == 0 {
+					lines = append(lines, "\t\n")
+					if len(v.title) > 0 {
+						lines = append(lines, fmt.Sprintf(" %s\t\n", v.title))
+					}
+				}
+				continue
+			case *infoValue:
+				if len(filter) == 0 {
+					lines = append(lines, fmt.Sprintf("     %s\n", v.String()))
+				}
+				continue
+			}
+			num++
+			config_map[num] = T.config[i]
+			if !matchDesc(T.config[i], filter) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf(" [%d] %s\n", num, T.config[i].String()))
+		}
+
+		if len(filter) > 0 && len(lines) == 0 {
+			lines = append(lines, fmt.Sprintf(" No options match '%s'.\n", filter))
+		}
+
+		page_size := T.page_size
+		if page_size <= 0 {
+			page_size = default_page_size
+		}
+		pages := (len(lines) + page_size - 1) / page_size
+		if page >= pages {
+			page = pages - 1
+		}
+		if page < 0 {
+			page = 0
+		}
+		if pages > 1 {
+			lines = lines[page*page_size:]
+			if len(lines) > page_size {
+				lines = lines[:page_size]
+			}
+		}
+
+		for _, line := range lines {
+			fmt.Fprint(txt, line)
+		}
+		fmt.Fprint(txt, last)
+
+		if len(filter) > 0 {
+			fmt.Fprintf(txt, "\n Showing options matching '%s', / to show all.\n", filter)
+		}
+		if pages > 1 {
+			fmt.Fprintf(txt, "\n Page %d of %d, < and > to change pages, /<text> to search.\n", page+1, pages)
 		}
 
 		fmt.Fprintf(txt, "\n%s: ", T.footer)
 		txt.Flush()
 
 		input := GetInput(text_buffer.String())
+		switch {
+		case input == "<":
+			page--
+			Stdout("\n")
+			continue
+		case input == ">":
+			page++
+			Stdout("\n")
+			continue
+		case strings.HasPrefix(input, "/"):
+			filter = strings.ToLower(strings.TrimSpace(input[1:]))
+			page = 0
+			if v := T.find(filter); v != nil {
+				filter = ""
+				choose(v)
+				continue
+			}
+			Stdout("\n")
+			continue
+		}
 		if strings.ToLower(input) == strings.ToLower(string(T.exit_char)) {
+			if missing := T.missing(); len(missing) > 0 {
+				Stdout("\n[ERROR] Required options are unconfigured: %s\n\n", strings.Join(missing, ", "))
+				continue
+			}
 			return
 		} else {
 			sel, err := strconv.Atoi(input)
@@ -88,17 +215,7 @@ func (T *Options) Select(separate_last bool) (changed bool) {
 				continue
 			} else {
 				if v, ok := config_map[sel]; ok {
-					changed = v.Set()
-					switch v.(type) {
-					case *funcValue:
-						Stdout("\n")
-						show_banner()
-					case *optionsValue:
-						Stdout("\n")
-						show_banner()
-					default:
-						Stdout("\n")
-					}
+					choose(v)
 					continue
 				}
 				Stdout("\n[ERROR] Unrecognized Selection: '%s'\n\n", input)
@@ -107,6 +224,32 @@ func (T *Options) Select(separate_last bool) (changed bool) {
 	}
 }
 
+// Returns true if the desc of option v contains filter.
+func matchDesc(v Value, filter string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	desc := strings.SplitN(v.String(), ":", 2)[0]
+	return strings.Contains(strings.ToLower(desc), filter)
+}
+
+// Returns the only selectable option matching filter, or nil if none or several match.
+func (T *Options) find(filter string) (found Value) {
+	if len(filter) == 0 {
+		return nil
+	}
+	for _, v := range T.config {
+		if !selectable(v) || !matchDesc(v, filter) {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = v
+	}
+	return found
+}
+
 // Presents string, uses astricks if private.
 func showVar(input string, mask bool) string {
 	hide_value := func(input string) string {
@@ -0,0 +1,101 @@
+package options
+
+import (
+	"fmt"
+	. "github.com/cmcoffee/snugforge/nfo"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Path defines a file or directory menu option displaying with specified desc in menu and default value def, a leading ~ is expanded to the home directory.
+// If must_exist is set the path must already exist, if dir_only is set the path must be a directory. The return value is the address of a string variable that stores the value of the option.
+func (O *Options) Path(desc string, def string, must_exist bool, dir_only bool) *string {
+	value := expandPath(def)
+	O.Register(&pathValue{
+		desc:       desc,
+		value:      &value,
+		must_exist: must_exist,
+		dir_only:   dir_only,
+	})
+	return &value
+}
+
+// Path value.
+type pathValue struct {
+	desc       string
+	value      *string
+	must_exist bool
+	dir_only   bool
+}
+
+// Expands a leading ~ to the home directory.
+func expandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// Checks path exists as required and can be used, ie.. a directory can be written to.
+func (P *pathValue) check(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if P.must_exist {
+			return fmt.Errorf("%s does not exist.", path)
+		}
+		return nil
+	}
+	if P.dir_only {
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory.", path)
+		}
+		f, err := os.CreateTemp(path, ".write_test")
+		if err != nil {
+			return fmt.Errorf("%s is not writable.", path)
+		}
+		f.Close()
+		os.Remove(f.Name())
+		return nil
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory.", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s is not readable.", path)
+	}
+	f.Close()
+	return nil
+}
+
+func (P *pathValue) Set() bool {
+	for {
+		input := GetInput(fmt.Sprintf("\n--> %s: ", P.desc))
+		if len(input) == 0 {
+			return false
+		}
+		path := expandPath(input)
+		if err := P.check(path); err != nil {
+			Stdout("\n[ERROR] %s", err)
+			continue
+		}
+		*P.value = path
+		return true
+	}
+}
+
+func (P *pathValue) Get() interface{} {
+	return P.value
+}
+
+func (P *pathValue) String() string {
+	return fmt.Sprintf("%s:\t%s", P.desc, showVar(*P.value, false))
+}
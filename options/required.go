@@ -0,0 +1,36 @@
+package options
+
+// Marks the options with desc as required, Select will not exit while any of them are unconfigured.
+func (T *Options) Required(desc ...string) {
+	if T.required == nil {
+		T.required = make(map[string]bool)
+	}
+	for _, v := range desc {
+		T.required[v] = true
+	}
+}
+
+// Returns the desc of required options that are unconfigured, including those of nested Options.
+func (T *Options) missing() (desc []string) {
+	for _, v := range T.config {
+		switch o := v.(type) {
+		case *stringValue:
+			if T.required[o.desc] && len(*o.value) == 0 {
+				desc = append(desc, o.desc)
+			}
+		case *pathValue:
+			if T.required[o.desc] && len(*o.value) == 0 {
+				desc = append(desc, o.desc)
+			}
+		case *stringsValue:
+			if T.required[o.desc] && len(*o.value) == 0 {
+				desc = append(desc, o.desc)
+			}
+		case *optionsValue:
+			for _, n := range o.value.missing() {
+				desc = append(desc, o.desc+"/"+n)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package options
+
+import (
+	"fmt"
+	. "github.com/cmcoffee/snugforge/nfo"
+	"strconv"
+	"strings"
+)
+
+// Strings defines a list menu option displaying with specified desc in menu and default value def, selecting it opens a sub-menu to add, delete and move entries. The return value is the address of a string slice that stores the value of the option.
+func (O *Options) Strings(desc string, def []string) *[]string {
+	value := append([]string(nil), def...)
+	O.Register(&stringsValue{
+		desc:  desc,
+		value: &value,
+	})
+	return &value
+}
+
+// String slice value.
+type stringsValue struct {
+	desc  string
+	value *[]string
+}
+
+// Splits a comma separated list, dropping blank entries.
+func splitList(input string) (list []string) {
+	for _, v := range strings.Split(input, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+func (S *stringsValue) Set() (changed bool) {
+	// Returns the index of an entry numbered by input.
+	entry := func(input string) (int, bool) {
+		n, err := strconv.Atoi(input)
+		if err != nil || n < 1 || n > len(*S.value) {
+			Stdout("\n[ERROR] Entry must be a number between 1 and %d.\n", len(*S.value))
+			return 0, false
+		}
+		return n - 1, true
+	}
+
+	for {
+		var text []string
+		text = append(text, fmt.Sprintf("\n--> %s:", S.desc))
+		if len(*S.value) == 0 {
+			text = append(text, " (empty)")
+		}
+		for i, v := range *S.value {
+			text = append(text, fmt.Sprintf(" [%d] %s", i+1, v))
+		}
+		text = append(text, "\n(a)dd <entry>, (d)elete <#>, (m)ove <#> <#>, (q)uit: ")
+
+		fields := strings.Fields(GetInput(strings.Join(text, "\n")))
+		if len(fields) == 0 {
+			continue
+		}
+		args := fields[1:]
+
+		switch strings.ToLower(fields[0]) {
+		case "q":
+			return
+		case "a":
+			if len(args) == 0 {
+				Stdout("\n[ERROR] Nothing to add.\n")
+				continue
+			}
+			*S.value = append(*S.value, strings.Join(args, " "))
+			changed = true
+		case "d":
+			if len(args) != 1 {
+				Stdout("\n[ERROR] Delete requires an entry number.\n")
+				continue
+			}
+			if i, ok := entry(args[0]); ok {
+				*S.value = append((*S.value)[:i], (*S.value)[i+1:]...)
+				changed = true
+			}
+		case "m":
+			if len(args) != 2 {
+				Stdout("\n[ERROR] Move requires an entry number and its new position.\n")
+				continue
+			}
+			from, ok := entry(args[0])
+			if !ok {
+				continue
+			}
+			to, ok := entry(args[1])
+			if !ok {
+				continue
+			}
+			v := (*S.value)[from]
+			*S.value = append((*S.value)[:from], (*S.value)[from+1:]...)
+			*S.value = append((*S.value)[:to], append([]string{v}, (*S.value)[to:]...)...)
+			changed = true
+		default:
+			Stdout("\n[ERROR] Unrecognized Selection: '%s'\n", fields[0])
+		}
+	}
+}
+
+func (S *stringsValue) Get() interface{} {
+	return S.value
+}
+
+func (S *stringsValue) String() string {
+	return fmt.Sprintf("%s:\t%s", S.desc, showVar(strings.Join(*S.value, ", "), false))
+}
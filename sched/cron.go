@@ -0,0 +1,143 @@
+package sched
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parsed cron schedule, each field is a bitmask of allowed values.
+type cronSpec struct {
+	minute   uint64
+	hour     uint64
+	dom      uint64
+	month    uint64
+	dow      uint64
+	dom_star bool
+	dow_star bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parses a five field cron spec.
+func parseCron(spec string) (c *cronSpec, err error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("sched: cron spec '%s' must have 5 fields, found %d", spec, len(fields))
+	}
+
+	c = new(cronSpec)
+	bounds := []struct {
+		mask     *uint64
+		min, max int
+	}{
+		{&c.minute, 0, 59},
+		{&c.hour, 0, 23},
+		{&c.dom, 1, 31},
+		{&c.month, 1, 12},
+		{&c.dow, 0, 7},
+	}
+	for i, b := range bounds {
+		if *b.mask, err = parseField(fields[i], b.min, b.max); err != nil {
+			return nil, fmt.Errorf("sched: cron spec '%s': %s", spec, err)
+		}
+	}
+	// Sunday is both 0 and 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.dom_star = strings.HasPrefix(fields[2], "*")
+	c.dow_star = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// Parses a comma separated list of values, ranges and steps into a bitmask.
+func parseField(field string, min, max int) (mask uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if n := strings.Index(part, "/"); n > -1 {
+			if step, err = strconv.Atoi(part[n+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in '%s'", part)
+			}
+			part = part[:n]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			if lo, err = strconv.Atoi(r[0]); err != nil {
+				return 0, fmt.Errorf("invalid range '%s'", part)
+			}
+			if hi, err = strconv.Atoi(r[1]); err != nil {
+				return 0, fmt.Errorf("invalid range '%s'", part)
+			}
+		default:
+			if lo, err = strconv.Atoi(part); err != nil {
+				return 0, fmt.Errorf("invalid value '%s'", part)
+			}
+			if step == 1 {
+				hi = lo
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("'%s' out of range %d-%d", part, min, max)
+		}
+		for i := lo; i <= hi; i += step {
+			mask |= 1 << uint(i)
+		}
+	}
+	return mask, nil
+}
+
+// Checks the day of month and day of week, if both are restricted either may match.
+func (c *cronSpec) matchDay(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.dom_star || c.dow_star {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Returns the first time after from matching the schedule, or a zero time if none within five years.
+func (c *cronSpec) next(from time.Time) time.Time {
+	loc := from.Location()
+	t := time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute()+1, 0, 0, loc)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
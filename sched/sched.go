@@ -0,0 +1,169 @@
+/*
+Package 'sched' runs tasks at intervals or on cron schedules, recovering and logging panics through nfo.
+Running tasks block nfo's shutdown, and the Scheduler is stopped by nfo's global defer, so jobs finish safely on exit.
+*/
+package sched
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cmcoffee/snugforge/nfo"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStopped   = errors.New("Scheduler has been stopped.")
+	ErrDuplicate = errors.New("Task with that name is already scheduled.")
+)
+
+// Scheduler of periodic tasks.
+type Scheduler struct {
+	mutex   sync.Mutex
+	tasks   map[string]*task
+	stopped bool
+	undefer func() error
+}
+
+type task struct {
+	name string
+	fn   func() error
+	next func(from time.Time) time.Time
+	stop chan struct{}
+}
+
+// Creates a new Scheduler, which is stopped automatically on nfo shutdown.
+func New() *Scheduler {
+	S := &Scheduler{tasks: make(map[string]*task)}
+	S.undefer = nfo.Defer(S.stop)
+	return S
+}
+
+// Runs fn every interval, starting one interval from now.
+func (S *Scheduler) Every(name string, interval time.Duration, fn func() error) error {
+	if interval <= 0 {
+		return fmt.Errorf("sched: invalid interval %s for task '%s'", interval, name)
+	}
+	return S.add(name, fn, func(from time.Time) time.Time {
+		return from.Add(interval)
+	})
+}
+
+// Runs fn on the cron schedule spec, ie.. "*/15 * * * *" or "@daily".
+// spec is five fields: minute, hour, day of month, month and day of week, each a '*', value, range, list or step.
+func (S *Scheduler) Cron(name string, spec string, fn func() error) error {
+	c, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	return S.add(name, fn, c.next)
+}
+
+// Schedules the task.
+func (S *Scheduler) add(name string, fn func() error, next func(from time.Time) time.Time) error {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+	if S.stopped {
+		return ErrStopped
+	}
+	if _, ok := S.tasks[name]; ok {
+		return ErrDuplicate
+	}
+	t := &task{
+		name: name,
+		fn:   fn,
+		next: next,
+		stop: make(chan struct{}),
+	}
+	S.tasks[name] = t
+	go S.loop(t)
+	return nil
+}
+
+// Removes the named task, returns false if no such task is scheduled.
+// A run in progress is allowed to finish.
+func (S *Scheduler) Remove(name string) bool {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+	t, ok := S.tasks[name]
+	if ok {
+		close(t.stop)
+		delete(S.tasks, name)
+	}
+	return ok
+}
+
+// Returns the names of scheduled tasks.
+func (S *Scheduler) Tasks() (names []string) {
+	S.mutex.Lock()
+	defer S.mutex.Unlock()
+	for name := range S.tasks {
+		names = append(names, name)
+	}
+	return
+}
+
+// Stops all tasks, runs in progress are left to finish, holding off nfo's shutdown until they do.
+// Stop may be called from within a task.
+func (S *Scheduler) Stop() {
+	S.undefer()
+}
+
+// Stops all tasks, called by Stop or nfo's global defer.
+func (S *Scheduler) stop() {
+	S.mutex.Lock()
+	if !S.stopped {
+		S.stopped = true
+		for name, t := range S.tasks {
+			close(t.stop)
+			delete(S.tasks, name)
+		}
+	}
+	S.mutex.Unlock()
+}
+
+// Waits for each scheduled time and runs the task, until stopped.
+func (S *Scheduler) loop(t *task) {
+	for {
+		next := t.next(time.Now())
+		if next.IsZero() {
+			nfo.Err("sched: task '%s' has no future run time, removing.", t.name)
+			S.Remove(t.name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-t.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		// Shutdown may have begun while we waited.
+		select {
+		case <-t.stop:
+			return
+		default:
+		}
+		if nfo.ShutdownInProgress() {
+			return
+		}
+		S.run(t)
+	}
+}
+
+// Runs the task, holding off shutdown until done, and logs any error or panic.
+// A task may call nfo.Fatal, as shutdown on Fatal doesn't wait on holds, a task calling nfo.Exit waits for nfo.SetShutdownTimeout.
+func (S *Scheduler) run(t *task) {
+	nfo.BlockShutdown()
+	defer nfo.UnblockShutdown()
+	defer func() {
+		if r := recover(); r != nil {
+			nfo.Err("sched: task '%s' panicked: %v\n%s", t.name, r, string(debug.Stack()))
+		}
+	}()
+	nfo.Trace("sched: running task '%s'.", t.name)
+	if err := t.fn(); err != nil {
+		nfo.Err("sched: task '%s': %s", t.name, err)
+	}
+}
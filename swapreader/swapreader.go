@@ -1,57 +1,170 @@
 package swapreader
 
 import (
+	"bytes"
+	"errors"
 	"io"
+	"sync"
 )
 
+var ErrNoRewind = errors.New("Current source does not support rewinding.")
+
 // Swap Reader allows for swapping the io.Reader backed []bytes
 type Reader struct {
 	from_reader    bool
 	reader         io.Reader
 	decoder_bytes  []byte
 	decoder_copied int
+	queue          []io.Reader
+	mutex          *sync.Mutex
+}
+
+// Enables concurrency-safe mode, so one goroutine may Set or Queue sources while another reads.
+// Reads from an io.Reader source happen outside of the lock, so feeding is not blocked by a waiting Read.
+func (r *Reader) ThreadSafe() {
+	if r.mutex == nil {
+		r.mutex = new(sync.Mutex)
+	}
+}
+
+func (r *Reader) lock() {
+	if r.mutex != nil {
+		r.mutex.Lock()
+	}
+}
+
+func (r *Reader) unlock() {
+	if r.mutex != nil {
+		r.mutex.Unlock()
+	}
 }
 
 // Set []byte for reader
 func (r *Reader) SetBytes(in []byte) {
+	r.lock()
+	defer r.unlock()
 	r.from_reader = false
+	r.reader = nil
 	r.decoder_bytes = in
 	r.decoder_copied = 0
+	r.queue = nil
+}
+
+// Set string for reader
+func (r *Reader) SetString(in string) {
+	r.SetBytes([]byte(in))
 }
 
 // Set Reader to Reader
 func (r *Reader) SetReader(in io.Reader) {
+	r.lock()
+	defer r.unlock()
 	r.from_reader = true
 	r.reader = in
+	r.decoder_bytes = nil
+	r.decoder_copied = 0
+	r.queue = nil
 }
 
-// swap_reader Read function.
-func (r *Reader) Read(p []byte) (n int, err error) {
+// Clears all sources, the Reader returns io.EOF until a new source is set.
+func (r *Reader) Reset() {
+	r.SetBytes(nil)
+}
 
+// Rewinds the current source to the beginning, for the []byte source or an io.Reader source implementing io.Seeker.
+func (r *Reader) Rewind() (err error) {
+	r.lock()
+	defer r.unlock()
 	if !r.from_reader {
-		buffer_len := len(r.decoder_bytes) - r.decoder_copied
+		r.decoder_copied = 0
+		return nil
+	}
+	if seeker, ok := r.reader.(io.Seeker); ok {
+		_, err = seeker.Seek(0, io.SeekStart)
+		return err
+	}
+	return ErrNoRewind
+}
 
-		if len(p) <= buffer_len {
-			for i := 0; i < len(p); i++ {
-				p[i] = r.decoder_bytes[r.decoder_copied]
-				r.decoder_copied++
-			}
-		} else {
-			for i := 0; i < buffer_len; i++ {
-				p[i] = r.decoder_bytes[r.decoder_copied]
-				r.decoder_copied++
-			}
-		}
+// Queue sources to be read in order after the current source, io.EOF is only returned once all sources are exhausted.
+func (r *Reader) Queue(in ...io.Reader) {
+	r.lock()
+	defer r.unlock()
+	r.queue = append(r.queue, in...)
+}
 
-		transferred := len(r.decoder_bytes) - r.decoder_copied
+// Queue []byte to be read after the current source.
+func (r *Reader) QueueBytes(in []byte) {
+	r.Queue(bytes.NewReader(in))
+}
 
-		if transferred == 0 {
+// Advances to the next queued source, returns false if none remain.
+func (r *Reader) next() bool {
+	if len(r.queue) == 0 {
+		return false
+	}
+	r.from_reader = true
+	r.reader = r.queue[0]
+	r.decoder_bytes = nil
+	r.decoder_copied = 0
+	r.queue[0] = nil
+	r.queue = r.queue[1:]
+	return true
+}
+
+// swap_reader Read function.
+func (r *Reader) Read(p []byte) (n int, err error) {
+	r.lock()
+	defer r.unlock()
+
+	if !r.from_reader {
+		n = copy(p, r.decoder_bytes[r.decoder_copied:])
+		r.decoder_copied += n
+		if r.decoder_copied == len(r.decoder_bytes) {
 			err = io.EOF
 		}
-
-		return buffer_len - transferred, err
+	} else if source := r.reader; source != nil {
+		r.unlock()
+		n, err = source.Read(p)
+		r.lock()
+		// Source was swapped during the read.
+		if source != r.reader {
+			if err == io.EOF {
+				err = nil
+			}
+			return n, err
+		}
 	} else {
-		return r.Read(p)
+		err = io.EOF
+	}
+
+	// Current source exhausted, move on to the next queued source.
+	if err == io.EOF && r.next() {
+		err = nil
 	}
+	return n, err
+}
+
+// Implements io.WriterTo, writes the remaining contents of all sources to w.
+func (r *Reader) WriteTo(w io.Writer) (n int64, err error) {
+	r.lock()
+	defer r.unlock()
 
+	for {
+		var written int64
+		if !r.from_reader {
+			var wn int
+			wn, err = w.Write(r.decoder_bytes[r.decoder_copied:])
+			r.decoder_copied += wn
+			written = int64(wn)
+		} else if source := r.reader; source != nil {
+			r.unlock()
+			written, err = io.Copy(w, source)
+			r.lock()
+		}
+		n += written
+		if err != nil || !r.next() {
+			return n, err
+		}
+	}
 }
@@ -0,0 +1,105 @@
+package swapreader
+
+import (
+	"io"
+	"sync"
+)
+
+// Swap Writer allows for swapping the destination of writes between a []byte buffer and an io.Writer.
+type Writer struct {
+	to_writer bool
+	writer    io.Writer
+	buffer    []byte
+	mutex     *sync.Mutex
+}
+
+// Enables concurrency-safe mode, so one goroutine may swap destinations while another writes.
+func (w *Writer) ThreadSafe() {
+	if w.mutex == nil {
+		w.mutex = new(sync.Mutex)
+	}
+}
+
+func (w *Writer) lock() {
+	if w.mutex != nil {
+		w.mutex.Lock()
+	}
+}
+
+func (w *Writer) unlock() {
+	if w.mutex != nil {
+		w.mutex.Unlock()
+	}
+}
+
+// Set []byte for writer, writes are appended to in[:0], reusing its capacity.
+func (w *Writer) SetBytes(in []byte) {
+	w.lock()
+	defer w.unlock()
+	w.to_writer = false
+	w.writer = nil
+	w.buffer = in[0:0]
+}
+
+// Set Writer to Writer
+func (w *Writer) SetWriter(out io.Writer) {
+	w.lock()
+	defer w.unlock()
+	w.to_writer = true
+	w.writer = out
+}
+
+// Returns the contents of the []byte buffer.
+func (w *Writer) Bytes() []byte {
+	w.lock()
+	defer w.unlock()
+	return w.buffer
+}
+
+// Returns the contents of the []byte buffer as a string.
+func (w *Writer) String() string {
+	return string(w.Bytes())
+}
+
+// Clears the []byte buffer, keeping its capacity, and returns to writing to the buffer.
+func (w *Writer) Reset() {
+	w.SetBytes(w.buffer)
+}
+
+// swap_writer Write function.
+func (w *Writer) Write(p []byte) (n int, err error) {
+	w.lock()
+	defer w.unlock()
+	if w.to_writer {
+		if w.writer == nil {
+			return 0, io.ErrClosedPipe
+		}
+		return w.writer.Write(p)
+	}
+	w.buffer = append(w.buffer, p...)
+	return len(p), nil
+}
+
+// Implements io.ReaderFrom, writes the contents of src to the current destination.
+func (w *Writer) ReadFrom(src io.Reader) (n int64, err error) {
+	w.lock()
+	defer w.unlock()
+	if w.to_writer {
+		if w.writer == nil {
+			return 0, io.ErrClosedPipe
+		}
+		return io.Copy(w.writer, src)
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		rn, rerr := src.Read(buf)
+		w.buffer = append(w.buffer, buf[0:rn]...)
+		n += int64(rn)
+		if rerr == io.EOF {
+			return n, nil
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package wrotate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type datedFile struct {
+	name          string
+	max_bytes     int64
+	max_rotations uint
+	interval      time.Duration
+	offset        time.Duration
+	max_periods   uint
+	file          io.WriteCloser
+	next          time.Time
+	lock          sync.Mutex
+}
+
+// Creates a new log file (or opens an existing one) rotated every interval, starting offset past midnight, ie.. 24*time.Hour, 2*time.Hour for daily at 02:00.
+// Rotated files are named by the start of their period, ie.. app.log.2006-01-02, or app.log.2006-01-02T15-04 for intervals under a day.
+// max_bytes and max_rotations rotate by size within a period as OpenFile does, max_periods is the number of previous periods to hold on to, 0 keeps all.
+func OpenDatedFile(name string, max_bytes int64, max_rotations uint, interval, offset time.Duration, max_periods uint) (io.WriteCloser, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("rotation interval must be greater than 0")
+	}
+
+	D := &datedFile{
+		name:          name,
+		max_bytes:     max_bytes,
+		max_rotations: max_rotations,
+		interval:      interval,
+		offset:        offset,
+		max_periods:   max_periods,
+	}
+
+	// Rotate a file left over from a previous period.
+	if finfo, err := os.Stat(name); err == nil && finfo.Size() > 0 {
+		if start := D.periodStart(finfo.ModTime()); start.Before(D.periodStart(time.Now())) {
+			if err := D.rotate(start); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := D.open(time.Now()); err != nil {
+		return nil, err
+	}
+	return D, nil
+}
+
+// Returns the start of the period containing t.
+func (D *datedFile) periodStart(t time.Time) time.Time {
+	year, mon, day := t.Date()
+	start := time.Date(year, mon, day, 0, 0, 0, 0, t.Location()).Add(D.offset)
+	for start.After(t) {
+		start = start.Add(-D.interval)
+	}
+	for !start.Add(D.interval).After(t) {
+		start = start.Add(D.interval)
+	}
+	return start
+}
+
+// Returns the layout of file suffixes.
+func (D *datedFile) layout() string {
+	if D.interval < 24*time.Hour {
+		return "2006-01-02T15-04"
+	}
+	return "2006-01-02"
+}
+
+// Opens the log file for the period containing now.
+func (D *datedFile) open(now time.Time) (err error) {
+	D.file, err = OpenFile(D.name, D.max_bytes, D.max_rotations)
+	D.next = D.periodStart(now).Add(D.interval)
+	return err
+}
+
+// Renames the log file, and any files rotated by size, to the period starting at start.
+func (D *datedFile) rotate(start time.Time) error {
+	stamp := start.Format(D.layout())
+	for i := D.max_rotations; i > 0; i-- {
+		old := fmt.Sprintf("%s.%d", D.name, i)
+		if _, err := os.Stat(old); err == nil {
+			if err := os.Rename(old, fmt.Sprintf("%s.%s.%d", D.name, stamp, i)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(D.name, fmt.Sprintf("%s.%s", D.name, stamp)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	D.prune()
+	return nil
+}
+
+// Removes files of periods beyond max_periods.
+func (D *datedFile) prune() {
+	if D.max_periods == 0 {
+		return
+	}
+	fpath, fname := filepath.Split(D.name)
+	if fpath == "" {
+		fpath = fmt.Sprintf(".%s", string(os.PathSeparator))
+	}
+	flist, err := os.ReadDir(fpath)
+	if err != nil {
+		return
+	}
+
+	periods := make(map[string][]string)
+	for _, v := range flist {
+		suffix := strings.TrimPrefix(v.Name(), fname+".")
+		if suffix == v.Name() {
+			continue
+		}
+		stamp := strings.SplitN(suffix, ".", 2)[0]
+		if _, err := time.Parse(D.layout(), stamp); err != nil {
+			continue
+		}
+		periods[stamp] = append(periods[stamp], v.Name())
+	}
+
+	var stamps []string
+	for stamp := range periods {
+		stamps = append(stamps, stamp)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(stamps)))
+
+	for i := int(D.max_periods); i < len(stamps); i++ {
+		for _, f := range periods[stamps[i]] {
+			os.Remove(filepath.Join(fpath, f))
+		}
+	}
+}
+
+// Write function that rotates the file when the current period ends.
+func (D *datedFile) Write(p []byte) (n int, err error) {
+	D.lock.Lock()
+	defer D.lock.Unlock()
+
+	if now := time.Now(); !now.Before(D.next) {
+		start := D.next.Add(-D.interval)
+		if err = D.file.Close(); err != nil {
+			return 0, err
+		}
+		if err = D.rotate(start); err != nil {
+			return 0, err
+		}
+		if err = D.open(now); err != nil {
+			return 0, err
+		}
+	}
+	return D.file.Write(p)
+}
+
+// Closes the log file.
+func (D *datedFile) Close() error {
+	D.lock.Lock()
+	defer D.lock.Unlock()
+	return D.file.Close()
+}
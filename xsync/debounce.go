@@ -0,0 +1,58 @@
+package xsync
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a function which delays calling fn until d has passed without another call, coalescing bursts into one call.
+func Debounce(d time.Duration, fn func()) func() {
+	var (
+		mutex sync.Mutex
+		timer *time.Timer
+	)
+	return func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, fn)
+	}
+}
+
+// Throttle returns a function which calls fn at most once per d, the first call runs immediately and calls made during the wait are coalesced into one trailing call.
+func Throttle(d time.Duration, fn func()) func() {
+	var (
+		mutex   sync.Mutex
+		waiting bool
+		pending bool
+	)
+
+	var fire func()
+	fire = func() {
+		mutex.Lock()
+		if !pending {
+			waiting = false
+			mutex.Unlock()
+			return
+		}
+		pending = false
+		mutex.Unlock()
+		fn()
+		time.AfterFunc(d, fire)
+	}
+
+	return func() {
+		mutex.Lock()
+		if waiting {
+			pending = true
+			mutex.Unlock()
+			return
+		}
+		waiting = true
+		mutex.Unlock()
+		fn()
+		time.AfterFunc(d, fire)
+	}
+}
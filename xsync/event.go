@@ -0,0 +1,72 @@
+package xsync
+
+import (
+	"sync"
+	"time"
+)
+
+// Event releases all waiters when Set, and stays set until Reset.
+type Event struct {
+	mutex sync.Mutex
+	ch    chan struct{}
+	set   bool
+}
+
+// Returns channel for the current cycle, creating it if needed.
+func (E *Event) channel() chan struct{} {
+	if E.ch == nil {
+		E.ch = make(chan struct{})
+	}
+	return E.ch
+}
+
+// Set releases all current and future waiters, until Reset.
+func (E *Event) Set() {
+	E.mutex.Lock()
+	defer E.mutex.Unlock()
+	if !E.set {
+		E.set = true
+		close(E.channel())
+	}
+}
+
+// Reset clears the Event, so Wait blocks until the next Set.
+func (E *Event) Reset() {
+	E.mutex.Lock()
+	defer E.mutex.Unlock()
+	if E.set {
+		E.set = false
+		E.ch = make(chan struct{})
+	}
+}
+
+// IsSet returns true if the Event is set.
+func (E *Event) IsSet() bool {
+	E.mutex.Lock()
+	defer E.mutex.Unlock()
+	return E.set
+}
+
+// Done returns a channel which is closed when the Event is Set, for use in select.
+func (E *Event) Done() <-chan struct{} {
+	E.mutex.Lock()
+	defer E.mutex.Unlock()
+	return E.channel()
+}
+
+// Wait blocks until the Event is Set.
+func (E *Event) Wait() {
+	<-E.Done()
+}
+
+// WaitTimeout blocks until the Event is Set or timeout is reached, returns true if Set.
+func (E *Event) WaitTimeout(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-E.Done():
+		return true
+	case <-timer.C:
+		return false
+	}
+}
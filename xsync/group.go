@@ -0,0 +1,90 @@
+/*
+Group is a sync.WaitGroup for tasks returning errors, with an optional limiter and cancellation on first error.
+*/
+package xsync
+
+import (
+	"context"
+	"sync"
+)
+
+type group struct {
+	ctx     context.Context
+	wg      sync.WaitGroup
+	limiter chan struct{}
+	cancel  context.CancelFunc
+	once    sync.Once
+	err     error
+	block   func()
+	unblock func()
+}
+
+type Group interface {
+	Go(task func() error)                // Runs task in a new goroutine once the limiter allows, skipping it if the context is cancelled first.
+	Wait() error                         // Waits for all tasks, returning the first error.
+	BlockShutdown(block, unblock func()) // Sets shutdown blockers held by each task while it runs.
+}
+
+// Creates a new Group, max limits how many tasks run at once, 0 is unlimited.
+// The returned context is cancelled when a task returns an error, or when Wait returns.
+func NewGroup(ctx context.Context, max int) (Group, context.Context) {
+	x := new(group)
+	if max > 0 {
+		x.limiter = make(chan struct{}, max)
+	}
+	x.ctx, x.cancel = context.WithCancel(ctx)
+	return x, x.ctx
+}
+
+// Registers shutdown blockers, ie.. nfo.BlockShutdown and nfo.UnblockShutdown, held by each task while it runs.
+// Must be set before calling Go.
+func (G *group) BlockShutdown(block, unblock func()) {
+	G.block = block
+	G.unblock = unblock
+}
+
+// Runs task in a new goroutine, blocking while the limiter is full, the first error returned cancels the Group's context.
+// If the context is cancelled before the limiter allows task to run, task is skipped.
+func (G *group) Go(task func() error) {
+	G.wg.Add(1)
+	if G.limiter != nil {
+		select {
+		case G.limiter <- struct{}{}:
+			if G.ctx.Err() != nil {
+				<-G.limiter
+				G.wg.Done()
+				return
+			}
+		case <-G.ctx.Done():
+			G.wg.Done()
+			return
+		}
+	}
+	if G.block != nil {
+		G.block()
+	}
+	go func() {
+		defer func() {
+			if G.unblock != nil {
+				G.unblock()
+			}
+			if G.limiter != nil {
+				<-G.limiter
+			}
+			G.wg.Done()
+		}()
+		if err := task(); err != nil {
+			G.once.Do(func() {
+				G.err = err
+				G.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until all tasks have completed, returning the first error encountered.
+func (G *group) Wait() error {
+	G.wg.Wait()
+	G.cancel()
+	return G.err
+}
@@ -0,0 +1,81 @@
+package xsync
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Longest pause between attempts while waiting on a lock.
+const max_lock_backoff = 10 * time.Millisecond
+
+// Retries try with backoff until it succeeds or ctx is done.
+func lockCtx(ctx context.Context, try func() bool) bool {
+	if try() {
+		return true
+	}
+	backoff := 50 * time.Microsecond
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return try()
+		case <-timer.C:
+			if try() {
+				return true
+			}
+			if backoff *= 2; backoff > max_lock_backoff {
+				backoff = max_lock_backoff
+			}
+			timer.Reset(backoff)
+		}
+	}
+}
+
+// Waits on try for up to timeout.
+func lockTimeout(timeout time.Duration, try func() bool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return lockCtx(ctx, try)
+}
+
+// Mutex is a sync.Mutex which can give up waiting.
+type Mutex struct {
+	sync.Mutex
+}
+
+// LockTimeout attempts to lock, returns false if the lock was not acquired within timeout.
+func (M *Mutex) LockTimeout(timeout time.Duration) bool {
+	return lockTimeout(timeout, M.TryLock)
+}
+
+// LockCtx attempts to lock, returns false if the lock was not acquired before ctx is done.
+func (M *Mutex) LockCtx(ctx context.Context) bool {
+	return lockCtx(ctx, M.TryLock)
+}
+
+// RWMutex is a sync.RWMutex which can give up waiting.
+type RWMutex struct {
+	sync.RWMutex
+}
+
+// LockTimeout attempts to lock for writing, returns false if the lock was not acquired within timeout.
+func (M *RWMutex) LockTimeout(timeout time.Duration) bool {
+	return lockTimeout(timeout, M.TryLock)
+}
+
+// LockCtx attempts to lock for writing, returns false if the lock was not acquired before ctx is done.
+func (M *RWMutex) LockCtx(ctx context.Context) bool {
+	return lockCtx(ctx, M.TryLock)
+}
+
+// RLockTimeout attempts to lock for reading, returns false if the lock was not acquired within timeout.
+func (M *RWMutex) RLockTimeout(timeout time.Duration) bool {
+	return lockTimeout(timeout, M.TryRLock)
+}
+
+// RLockCtx attempts to lock for reading, returns false if the lock was not acquired before ctx is done.
+func (M *RWMutex) RLockCtx(ctx context.Context) bool {
+	return lockCtx(ctx, M.TryRLock)
+}
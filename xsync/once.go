@@ -0,0 +1,47 @@
+package xsync
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Once is a sync.Once which can be Reset to run again.
+type Once struct {
+	mutex sync.Mutex
+	done  uint32
+}
+
+// Do calls f if Do has not been called since creation or the last Reset.
+func (O *Once) Do(f func()) {
+	if atomic.LoadUint32(&O.done) == 1 {
+		return
+	}
+	O.mutex.Lock()
+	defer O.mutex.Unlock()
+	if O.done == 0 {
+		defer atomic.StoreUint32(&O.done, 1)
+		f()
+	}
+}
+
+// DoErr calls f if it has not yet succeeded, f is only considered done if it returns nil, allowing retry after failure.
+func (O *Once) DoErr(f func() error) (err error) {
+	if atomic.LoadUint32(&O.done) == 1 {
+		return nil
+	}
+	O.mutex.Lock()
+	defer O.mutex.Unlock()
+	if O.done == 0 {
+		if err = f(); err == nil {
+			atomic.StoreUint32(&O.done, 1)
+		}
+	}
+	return err
+}
+
+// Reset allows the next Do to run again.
+func (O *Once) Reset() {
+	O.mutex.Lock()
+	defer O.mutex.Unlock()
+	atomic.StoreUint32(&O.done, 0)
+}
@@ -0,0 +1,110 @@
+/*
+Pool is a fixed set of worker goroutines executing submitted tasks, with panics routed to a handler.
+*/
+package xsync
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+type pool struct {
+	tasks      chan func()
+	done       chan struct{}
+	pending    sync.WaitGroup
+	submitting sync.WaitGroup
+	workers    sync.WaitGroup
+	mutex      sync.RWMutex
+	stopped    bool
+	handler    func(err interface{}, stack []byte)
+}
+
+type Pool interface {
+	Submit(task func()) bool // Queues task, blocking while the Pool is busy, returns false if the Pool is stopped.
+	Wait()                   // Waits for all submitted tasks to complete.
+	Stop()                   // Refuses new tasks and waits for submitted ones to complete.
+}
+
+// Creates a new Pool with workers goroutines, panics within tasks are recovered and passed to panic_handler.
+// If panic_handler is nil, the panic and its stack are written with the standard log package.
+func NewPool(workers int, panic_handler func(err interface{}, stack []byte)) Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	x := &pool{
+		tasks:   make(chan func(), workers),
+		done:    make(chan struct{}),
+		handler: panic_handler,
+	}
+	if x.handler == nil {
+		x.handler = func(err interface{}, stack []byte) {
+			log.Printf("panic in pool task: %v\n%s", err, stack)
+		}
+	}
+	x.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go x.worker()
+	}
+	return x
+}
+
+// Executes tasks until the Pool is stopped.
+func (P *pool) worker() {
+	defer P.workers.Done()
+	for task := range P.tasks {
+		P.run(task)
+	}
+}
+
+// Runs task, recovering any panic.
+func (P *pool) run(task func()) {
+	defer P.pending.Done()
+	defer func() {
+		if r := recover(); r != nil {
+			P.handler(r, debug.Stack())
+		}
+	}()
+	task()
+}
+
+// Submit queues task, blocking while all workers are busy and the queue is full, returns false if the Pool is stopped.
+func (P *pool) Submit(task func()) bool {
+	P.mutex.RLock()
+	if P.stopped {
+		P.mutex.RUnlock()
+		return false
+	}
+	P.submitting.Add(1)
+	P.pending.Add(1)
+	P.mutex.RUnlock()
+	defer P.submitting.Done()
+
+	select {
+	case P.tasks <- task:
+		return true
+	case <-P.done:
+		P.pending.Done()
+		return false
+	}
+}
+
+// Wait blocks until all submitted tasks have completed.
+func (P *pool) Wait() {
+	P.pending.Wait()
+}
+
+// Stop refuses new tasks, including those of a Submit still blocked, then waits for submitted tasks to complete and the workers to exit.
+func (P *pool) Stop() {
+	P.mutex.Lock()
+	if !P.stopped {
+		P.stopped = true
+		close(P.done)
+		P.mutex.Unlock()
+		P.submitting.Wait()
+		close(P.tasks)
+	} else {
+		P.mutex.Unlock()
+	}
+	P.workers.Wait()
+}